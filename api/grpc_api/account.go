@@ -0,0 +1,204 @@
+package grpc_api
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/absk07/Go-Bank/worker"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateCreateAccountRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	args := db.CreateAccountTxParams{
+		CreateAccountParams: db.CreateAccountParams{
+			Owner:    payload.Username,
+			Currency: req.GetCurrency(),
+			Balance:  0,
+		},
+		AfterCreate: func(q db.Querier, account db.Account) error {
+			data := worker.AccountEventData{
+				Account: account,
+			}
+			return worker.DistributeWebhookEvent(ctx, q, worker.NewOutboxTaskDistributor(q), utils.WebhookEventAccountCreated, data, account.Owner)
+		},
+	}
+	txResult, err := server.store.CreateAccountTx(ctx, args)
+	if err != nil {
+		switch db.ErrorCode(err) {
+		case db.UniqueViolation:
+			return nil, status.Errorf(codes.AlreadyExists, "account with currency %s already exists", req.GetCurrency())
+		case db.ForeignKeyViolation:
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create account: %s", err)
+	}
+
+	return &pb.CreateAccountResponse{
+		Account: convertAccount(txResult.Account),
+	}, nil
+}
+
+func validateCreateAccountRequest(req *pb.CreateAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, helpers.FieldViolation("currency", err))
+	}
+	return violations
+}
+
+func (server *Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateGetAccountRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if !canView(payload, account.Owner) {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	return &pb.GetAccountResponse{
+		Account: convertAccount(account),
+	}, nil
+}
+
+func validateGetAccountRequest(req *pb.GetAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateId(req.GetId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("id", err))
+	}
+	return violations
+}
+
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateListAccountsRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	owner := payload.Username
+	if req.Owner != nil {
+		owner = req.GetOwner()
+	}
+	if !canView(payload, owner) {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot list other user's accounts")
+	}
+
+	args := db.ListAccountParams{
+		Owner:  owner,
+		Limit:  req.GetPageSize(),
+		Offset: (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+	accounts, err := server.store.ListAccount(ctx, args)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list accounts: %s", err)
+	}
+
+	rsp := &pb.ListAccountsResponse{
+		Accounts: make([]*pb.Account, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		rsp.Accounts = append(rsp.Accounts, convertAccount(account))
+	}
+	return rsp, nil
+}
+
+func validateListAccountsRequest(req *pb.ListAccountsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidatePageId(req.GetPageId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_id", err))
+	}
+	if err := utils.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_size", err))
+	}
+	if req.Owner != nil {
+		if err := utils.ValidateUsername(req.GetOwner()); err != nil {
+			violations = append(violations, helpers.FieldViolation("owner", err))
+		}
+	}
+	return violations
+}
+
+func (server *Server) UpdateAccount(ctx context.Context, req *pb.UpdateAccountRequest) (*pb.UpdateAccountResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+	if err := requireRole(payload, utils.AdminRole); err != nil {
+		return nil, err
+	}
+
+	violations := validateUpdateAccountRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+	if account.IsSystem() {
+		return nil, helpers.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			helpers.FieldViolation("id", db.ErrSystemAccount),
+		})
+	}
+
+	if req.OverdraftLimit != nil {
+		account, err = server.store.UpdateAccountOverdraftLimit(ctx, db.UpdateAccountOverdraftLimitParams{
+			ID:             account.ID,
+			OverdraftLimit: req.GetOverdraftLimit(),
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to update account: %s", err)
+		}
+	}
+
+	return &pb.UpdateAccountResponse{
+		Account: convertAccount(account),
+	}, nil
+}
+
+func validateUpdateAccountRequest(req *pb.UpdateAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateId(req.GetId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("id", err))
+	}
+	if req.OverdraftLimit != nil {
+		if err := utils.ValidateOverdraftLimit(req.GetOverdraftLimit()); err != nil {
+			violations = append(violations, helpers.FieldViolation("overdraft_limit", err))
+		}
+	}
+	return violations
+}
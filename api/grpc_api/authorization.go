@@ -2,11 +2,16 @@ package grpc_api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
+	db "github.com/absk07/Go-Bank/db/sqlc"
 	"github.com/absk07/Go-Bank/utils"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -14,28 +19,69 @@ const (
 	authorizationBearer = "bearer"
 )
 
-func (server *Server) authorizeUser(ctx context.Context) (string, error) {
+// authorizeUser returns the user the auth interceptor authenticated the request of. A handler called
+// without the interceptor authenticates the request itself.
+func (server *Server) authorizeUser(ctx context.Context) (*utils.Payload, error) {
+	if payload, ok := principalFromContext(ctx); ok {
+		return payload, nil
+	}
+	return server.authenticateRequest(ctx)
+}
+
+// authenticateRequest verifies the access token sent with the request, and the session it belongs to
+func (server *Server) authenticateRequest(ctx context.Context) (*utils.Payload, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return "", fmt.Errorf("missing metadata")
+		return nil, fmt.Errorf("missing metadata")
 	}
 	values := md.Get(authorizationHeader)
 	if len(values) == 0 {
-		return "", fmt.Errorf("missing authorization header")
+		return nil, fmt.Errorf("missing authorization header")
 	}
 	authHeader := values[0]
 	fields := strings.Fields(authHeader)
 	if len(fields) < 2 {
-		return "", fmt.Errorf("invalid authorization header format")
+		return nil, fmt.Errorf("invalid authorization header format")
 	}
 	authType := strings.ToLower(fields[0])
 	if authType != authorizationBearer {
-		return "", fmt.Errorf("unsupported authorization type: %s", authType)
+		return nil, fmt.Errorf("unsupported authorization type: %s", authType)
 	}
 	accessToken := fields[1]
-	_, payload, err := utils.VerifyToken(accessToken, server.config.Secret)
+	payload, err := server.tokenMaker.VerifyToken(accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access token: %s", err)
+	}
+	if payload.Type != utils.TokenTypeAccess {
+		return nil, fmt.Errorf("invalid access token: not an access token")
+	}
+
+	// the access tokens of a session stop working as soon as it is logged out or revoked
+	session, err := server.store.GetSession(ctx, payload.SessionID)
 	if err != nil {
-		return "", fmt.Errorf("invalid access token: %s", err)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, fmt.Errorf("session not found")
+		}
+		return nil, fmt.Errorf("failed to get session: %s", err)
+	}
+	if session.IsBlocked {
+		return nil, fmt.Errorf("session is blocked")
+	}
+	if session.Username != payload.Username {
+		return nil, fmt.Errorf("incorrect session user")
 	}
 	return payload, nil
 }
+
+// requireRole makes sure the authenticated user has one of roles
+func requireRole(payload *utils.Payload, roles ...string) error {
+	if !slices.Contains(roles, payload.Role) {
+		return status.Errorf(codes.PermissionDenied, "the %s role is not allowed to perform this action", payload.Role)
+	}
+	return nil
+}
+
+// canView reports whether the authenticated user may read the data of owner
+func canView(payload *utils.Payload, owner string) bool {
+	return payload.Username == owner || utils.IsStaffRole(payload.Role)
+}
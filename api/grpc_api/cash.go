@@ -0,0 +1,100 @@
+package grpc_api
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.DepositResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+	// the cash is taken from the bank's cash account, so only a teller who received it can deposit it
+	if err := requireRole(payload, utils.BankerRole, utils.AdminRole); err != nil {
+		return nil, err
+	}
+
+	violations := validateCashRequest(req.GetAccountId(), req.GetAmount(), req.GetCurrency())
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	account, err := server.validAccount(ctx, "account_id", req.GetAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	txResult, err := server.store.DepositTx(ctx, db.CashTxParams{
+		AccountID: account.ID,
+		Amount:    req.GetAmount(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to deposit money: %s", err)
+	}
+
+	return &pb.DepositResponse{
+		Transfer: convertTransfer(txResult.Transfer),
+		Account:  convertAccount(txResult.Account),
+		Entry:    convertEntry(txResult.Entry),
+	}, nil
+}
+
+func (server *Server) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.WithdrawResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateCashRequest(req.GetAccountId(), req.GetAmount(), req.GetCurrency())
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	account, err := server.validAccount(ctx, "account_id", req.GetAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+	// customers withdraw from their own accounts, the bank's staff can pay out cash for any customer
+	if account.Owner != payload.Username && !utils.IsStaffRole(payload.Role) {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	txResult, err := server.store.WithdrawTx(ctx, db.CashTxParams{
+		AccountID: account.ID,
+		Amount:    req.GetAmount(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to withdraw money: %s", err)
+	}
+
+	return &pb.WithdrawResponse{
+		Transfer: convertTransfer(txResult.Transfer),
+		Account:  convertAccount(txResult.Account),
+		Entry:    convertEntry(txResult.Entry),
+	}, nil
+}
+
+func validateCashRequest(accountID int64, amount int64, currency string) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateId(accountID); err != nil {
+		violations = append(violations, helpers.FieldViolation("account_id", err))
+	}
+	if err := utils.ValidateAmount(amount); err != nil {
+		violations = append(violations, helpers.FieldViolation("amount", err))
+	}
+	if err := utils.ValidateCurrency(currency); err != nil {
+		violations = append(violations, helpers.FieldViolation("currency", err))
+	}
+	return violations
+}
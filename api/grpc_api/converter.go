@@ -0,0 +1,172 @@
+package grpc_api
+
+import (
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func convertAccount(account db.Account) *pb.Account {
+	return &pb.Account{
+		Id:             account.ID,
+		Owner:          account.Owner,
+		Balance:        account.Balance,
+		Currency:       account.Currency,
+		CreatedAt:      timestamppb.New(account.CreatedAt.Time),
+		OverdraftLimit: account.OverdraftLimit,
+	}
+}
+
+func convertEntry(entry db.Entry) *pb.Entry {
+	return &pb.Entry{
+		Id:        entry.ID,
+		AccountId: entry.AccountID,
+		Amount:    entry.Amount,
+		CreatedAt: timestamppb.New(entry.CreatedAt.Time),
+	}
+}
+
+func convertTransfer(transfer db.Transfer) *pb.Transfer {
+	return &pb.Transfer{
+		Id:                 transfer.ID,
+		FromAccountId:      transfer.FromAccountID,
+		ToAccountId:        transfer.ToAccountID,
+		Amount:             transfer.Amount,
+		CreatedAt:          timestamppb.New(transfer.CreatedAt.Time),
+		ExchangeRate:       convertNumeric(transfer.ExchangeRate),
+		SourceTransferId:   transfer.SourceTransferID.Int64,
+		ReversedTransferId: transfer.ReversedTransferID.Int64,
+	}
+}
+
+func convertFxRate(fxRate db.FxRate) *pb.FxRate {
+	return &pb.FxRate{
+		Id:           fxRate.ID,
+		FromCurrency: fxRate.FromCurrency,
+		ToCurrency:   fxRate.ToCurrency,
+		Rate:         convertNumeric(fxRate.Rate),
+		EffectiveAt:  timestamppb.New(fxRate.EffectiveAt.Time),
+	}
+}
+
+// convertNumeric formats a numeric column as a decimal string, or an empty string if it is NULL
+func convertNumeric(value pgtype.Numeric) string {
+	if !value.Valid {
+		return ""
+	}
+	text, err := value.Value()
+	if err != nil {
+		return ""
+	}
+	str, _ := text.(string)
+	return str
+}
+
+func convertScheduledTransfer(scheduledTransfer db.ScheduledTransfer) *pb.ScheduledTransfer {
+	rsp := &pb.ScheduledTransfer{
+		Id:             scheduledTransfer.ID,
+		Owner:          scheduledTransfer.Owner,
+		FromAccountId:  scheduledTransfer.FromAccountID,
+		ToAccountId:    scheduledTransfer.ToAccountID,
+		Amount:         scheduledTransfer.Amount,
+		Frequency:      scheduledTransfer.Frequency,
+		CronExpression: scheduledTransfer.CronExpression,
+		Status:         scheduledTransfer.Status,
+		StartAt:        timestamppb.New(scheduledTransfer.StartAt.Time),
+		NextRunAt:      timestamppb.New(scheduledTransfer.NextRunAt.Time),
+		FailureCount:   scheduledTransfer.FailureCount,
+		CreatedAt:      timestamppb.New(scheduledTransfer.CreatedAt.Time),
+	}
+	if scheduledTransfer.LastRunAt.Valid {
+		rsp.LastRunAt = timestamppb.New(scheduledTransfer.LastRunAt.Time)
+	}
+	return rsp
+}
+
+func convertScheduledTransferRun(run db.ScheduledTransferRun) *pb.ScheduledTransferRun {
+	return &pb.ScheduledTransferRun{
+		Id:                  run.ID,
+		ScheduledTransferId: run.ScheduledTransferID,
+		TransferId:          run.TransferID.Int64,
+		Status:              run.Status,
+		Error:               run.Error,
+		ScheduledAt:         timestamppb.New(run.ScheduledAt.Time),
+		CreatedAt:           timestamppb.New(run.CreatedAt.Time),
+	}
+}
+
+func convertSession(session db.Session, currentSessionID uuid.UUID) *pb.Session {
+	return &pb.Session{
+		Id:        session.ID.String(),
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		IsBlocked: session.IsBlocked,
+		ExpiresAt: timestamppb.New(session.ExpiresAt.Time),
+		CreatedAt: timestamppb.New(session.CreatedAt.Time),
+		IsCurrent: session.ID == currentSessionID,
+	}
+}
+
+func convertSigningKey(signingKey db.SigningKey) *pb.SigningKey {
+	rsp := &pb.SigningKey{
+		Kid:       signingKey.Kid,
+		Algorithm: signingKey.Algorithm,
+		CreatedAt: timestamppb.New(signingKey.CreatedAt.Time),
+	}
+	if signingKey.RetiresAt.Valid {
+		rsp.RetiresAt = timestamppb.New(signingKey.RetiresAt.Time)
+	}
+	return rsp
+}
+
+func convertUser(user db.User) *pb.User {
+	return &pb.User{
+		Username:          user.Username,
+		FullName:          user.Fullname,
+		Email:             user.Email,
+		PasswordChangedAt: timestamppb.New(user.PasswordChangedAt.Time),
+		CreatedAt:         timestamppb.New(user.CreatedAt.Time),
+		Role:              user.Role,
+		PendingEmail:      user.PendingEmail.String,
+		Locale:            user.Locale,
+	}
+}
+
+func convertNotificationPreference(preference db.NotificationPreference) *pb.NotificationPreference {
+	rsp := &pb.NotificationPreference{
+		Channel:               preference.Channel,
+		TransferNotifications: preference.TransferNotifications,
+	}
+	if preference.UpdatedAt.Valid {
+		rsp.UpdatedAt = timestamppb.New(preference.UpdatedAt.Time)
+	}
+	return rsp
+}
+
+func convertWebhookEndpoint(endpoint db.WebhookEndpoint) *pb.WebhookEndpoint {
+	return &pb.WebhookEndpoint{
+		Id:         endpoint.ID,
+		Owner:      endpoint.Owner,
+		Url:        endpoint.Url,
+		EventTypes: endpoint.EventTypes,
+		CreatedAt:  timestamppb.New(endpoint.CreatedAt.Time),
+	}
+}
+
+func convertWebhookDelivery(delivery db.WebhookDelivery) *pb.WebhookDelivery {
+	return &pb.WebhookDelivery{
+		Id:             delivery.ID,
+		EndpointId:     delivery.EndpointID,
+		EventId:        delivery.EventID.String(),
+		EventType:      delivery.EventType,
+		Attempt:        delivery.Attempt,
+		Payload:        string(delivery.Payload),
+		ResponseStatus: delivery.ResponseStatus.Int32,
+		Error:          delivery.Error,
+		Succeeded:      delivery.Succeeded,
+		DurationMs:     delivery.DurationMs,
+		CreatedAt:      timestamppb.New(delivery.CreatedAt.Time),
+	}
+}
@@ -0,0 +1,54 @@
+package grpc_api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetExchangeRate(ctx context.Context, req *pb.GetExchangeRateRequest) (*pb.GetExchangeRateResponse, error) {
+	_, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateGetExchangeRateRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	fxRate, err := server.store.GetFxRate(ctx, db.GetFxRateParams{
+		FromCurrency: req.GetFromCurrency(),
+		ToCurrency:   req.GetToCurrency(),
+		At:           pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "no exchange rate in effect from %s to %s", req.GetFromCurrency(), req.GetToCurrency())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get exchange rate: %s", err)
+	}
+
+	return &pb.GetExchangeRateResponse{
+		FxRate: convertFxRate(fxRate),
+	}, nil
+}
+
+func validateGetExchangeRateRequest(req *pb.GetExchangeRateRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateCurrency(req.GetFromCurrency()); err != nil {
+		violations = append(violations, helpers.FieldViolation("from_currency", err))
+	}
+	if err := utils.ValidateCurrency(req.GetToCurrency()); err != nil {
+		violations = append(violations, helpers.FieldViolation("to_currency", err))
+	}
+	return violations
+}
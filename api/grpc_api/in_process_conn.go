@@ -0,0 +1,118 @@
+package grpc_api
+
+import (
+	"context"
+	"sync"
+
+	"github.com/absk07/Go-Bank/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// inProcessConn is a client connection calling the methods of a server directly, through the same
+// interceptors as the gRPC server. The gateway uses it, so that its calls are handled like gRPC calls
+// without going through the network.
+type inProcessConn struct {
+	server      *Server
+	methods     map[string]grpc.MethodDesc
+	interceptor grpc.UnaryServerInterceptor
+}
+
+// NewInProcessConn creates a client connection to server, calling interceptors in order around every call
+func NewInProcessConn(server *Server, interceptors ...grpc.UnaryServerInterceptor) grpc.ClientConnInterface {
+	methods := make(map[string]grpc.MethodDesc, len(pb.GoBank_ServiceDesc.Methods))
+	for _, method := range pb.GoBank_ServiceDesc.Methods {
+		methods["/"+pb.GoBank_ServiceDesc.ServiceName+"/"+method.MethodName] = method
+	}
+	return &inProcessConn{
+		server:      server,
+		methods:     methods,
+		interceptor: chainUnaryInterceptors(interceptors),
+	}
+}
+
+func (conn *inProcessConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	desc, ok := conn.methods[method]
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+	}
+
+	// the metadata sent by the client is received by the server, as it would be over the network
+	md, _ := metadata.FromOutgoingContext(ctx)
+	ctx = metadata.NewIncomingContext(ctx, md.Copy())
+	stream := &inProcessStream{method: method}
+	ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+
+	decode := func(req any) error {
+		proto.Merge(req.(proto.Message), args.(proto.Message))
+		return nil
+	}
+	rsp, err := desc.Handler(conn.server, ctx, decode, conn.interceptor)
+
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case grpc.HeaderCallOption:
+			*opt.HeaderAddr = stream.header
+		case grpc.TrailerCallOption:
+			*opt.TrailerAddr = stream.trailer
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	proto.Reset(reply.(proto.Message))
+	proto.Merge(reply.(proto.Message), rsp.(proto.Message))
+	return nil
+}
+
+func (conn *inProcessConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "streaming method %s cannot be called in process", method)
+}
+
+// inProcessStream collects the headers and trailers a handler sets with grpc.SetHeader and grpc.SetTrailer
+type inProcessStream struct {
+	method  string
+	mu      sync.Mutex
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (stream *inProcessStream) Method() string {
+	return stream.method
+}
+
+func (stream *inProcessStream) SetHeader(md metadata.MD) error {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	stream.header = metadata.Join(stream.header, md)
+	return nil
+}
+
+func (stream *inProcessStream) SendHeader(md metadata.MD) error {
+	return stream.SetHeader(md)
+}
+
+func (stream *inProcessStream) SetTrailer(md metadata.MD) error {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	stream.trailer = metadata.Join(stream.trailer, md)
+	return nil
+}
+
+// chainUnaryInterceptors combines interceptors into one, calling them in order like grpc.ChainUnaryInterceptor
+func chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
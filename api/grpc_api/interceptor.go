@@ -0,0 +1,96 @@
+package grpc_api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"google.golang.org/grpc"
+)
+
+type methodPolicy int
+
+const (
+	// protectedMethod requires a valid access token. It is the policy of every method not listed in methodPolicies.
+	protectedMethod methodPolicy = iota
+	// publicMethod can be called without an access token
+	publicMethod
+)
+
+var methodPolicies = map[string]methodPolicy{
+	pb.GoBank_RegisterUser_FullMethodName:         publicMethod,
+	pb.GoBank_LoginUser_FullMethodName:            publicMethod,
+	pb.GoBank_VerifyEmail_FullMethodName:          publicMethod,
+	pb.GoBank_RenewAccessToken_FullMethodName:     publicMethod,
+	pb.GoBank_GetJWKS_FullMethodName:              publicMethod,
+	pb.GoBank_VerifyLoginChallenge_FullMethodName: publicMethod,
+	pb.GoBank_RequestPasswordReset_FullMethodName: publicMethod,
+	pb.GoBank_ResetPassword_FullMethodName:        publicMethod,
+}
+
+// policyOf returns the policy of a method. The policies only cover the GoBank service,
+// other services registered on the gRPC server such as reflection are public.
+func policyOf(fullMethod string) methodPolicy {
+	if !strings.HasPrefix(fullMethod, "/"+pb.GoBank_ServiceDesc.ServiceName+"/") {
+		return publicMethod
+	}
+	return methodPolicies[fullMethod]
+}
+
+type principalKey struct{}
+
+// principalFromContext returns the user the auth interceptor authenticated
+func principalFromContext(ctx context.Context) (*utils.Payload, bool) {
+	payload, ok := ctx.Value(principalKey{}).(*utils.Payload)
+	return payload, ok
+}
+
+// authenticate checks the access token of a call to a protected method,
+// and returns the context carrying the authenticated user
+func (server *Server) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if policyOf(fullMethod) == publicMethod {
+		return ctx, nil
+	}
+	payload, err := server.authenticateRequest(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+	return context.WithValue(ctx, principalKey{}, payload), nil
+}
+
+// UnaryInterceptors returns the interceptors every unary call goes through, whether it comes
+// from a gRPC client or from the gateway
+func (server *Server) UnaryInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		utils.GrpcLogger,
+		server.UnaryAuthInterceptor,
+	}
+}
+
+func (server *Server) UnaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := server.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (server *Server) StreamAuthInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := server.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticatedStream is a server stream whose context carries the authenticated user
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authenticatedStream) Context() context.Context {
+	return stream.ctx
+}
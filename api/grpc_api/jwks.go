@@ -0,0 +1,33 @@
+package grpc_api
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// jsonWebKeySet is the JWK set document (RFC 7517 section 5)
+type jsonWebKeySet struct {
+	Keys []utils.JSONWebKey `json:"keys"`
+}
+
+func (server *Server) GetJWKS(ctx context.Context, req *pb.GetJWKSRequest) (*httpbody.HttpBody, error) {
+	keys := server.tokenMaker.PublicKeys()
+	if keys == nil {
+		keys = []utils.JSONWebKey{}
+	}
+
+	data, err := json.Marshal(jsonWebKeySet{Keys: keys})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode key set: %s", err)
+	}
+	return &httpbody.HttpBody{
+		ContentType: "application/jwk-set+json",
+		Data:        data,
+	}, nil
+}
@@ -2,7 +2,8 @@ package grpc_api
 
 import (
 	"context"
-	"database/sql"
+	"errors"
+	"time"
 
 	db "github.com/absk07/Go-Bank/db/sqlc"
 	"github.com/absk07/Go-Bank/helpers"
@@ -16,38 +17,91 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const loginChallengeDuration = 5 * time.Minute
+
 func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
 	violations := validateLoginUserRequest(req)
 	if violations != nil {
 		return nil, helpers.InvalidArgumentError(violations)
 	}
 
+	clientIP := loginClientIP(server.extractMetadata(ctx))
+	if err := server.checkLoginThrottle(ctx, clientIP); err != nil {
+		return nil, err
+	}
+
 	user, err := server.store.GetUser(ctx, req.GetUsername())
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			if err := server.recordFailedLogin(ctx, req.GetUsername(), clientIP); err != nil {
+				return nil, err
+			}
 			return nil, status.Errorf(codes.NotFound, "user not found: %s", err)
 		}
 		return nil, status.Errorf(codes.Internal, "internal server error: %s", err)
 	}
+	if err := checkLoginLockout(user); err != nil {
+		return nil, err
+	}
 	IsPasswordValid := utils.IsPasswordValid(req.GetPassword(), user.Password)
 	if !IsPasswordValid {
-		return nil, status.Errorf(codes.NotFound, "wrong credentials: %s", err)
+		if err := server.recordFailedLogin(ctx, user.Username, clientIP); err != nil {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.NotFound, "wrong credentials")
+	}
+
+	// with two-factor authentication the password only earns a challenge, exchanged for the tokens along with a code.
+	// The failed logins are kept until the code is right too, so that wrong codes add up to a lockout.
+	twoFactorEnabled, err := server.isTwoFactorEnabled(ctx, user.Username)
+	if err != nil {
+		return nil, err
 	}
-	var token, refreshToken string
-	var refreshTokenId uuid.UUID
-	var token_expiration, refreshToken_expiration pgtype.Timestamptz
-	_, token, token_expiration, err = utils.GenerateToken(
+	if twoFactorEnabled {
+		challenge, err := server.store.CreateLoginChallenge(ctx, db.CreateLoginChallengeParams{
+			ID:       uuid.New(),
+			Username: user.Username,
+			ExpiresAt: pgtype.Timestamptz{
+				Time:  time.Now().Add(loginChallengeDuration),
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create login challenge: %s", err)
+		}
+		return &pb.LoginUserResponse{
+			TwoFactorRequired:  true,
+			ChallengeToken:     challenge.ID.String(),
+			ChallengeExpiresAt: timestamppb.New(challenge.ExpiresAt.Time),
+		}, nil
+	}
+
+	user, err = server.resetFailedLogins(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return server.createLoginSession(ctx, user)
+}
+
+// createLoginSession creates a session for a user that proved who they are, with its access & refresh tokens
+func (server *Server) createLoginSession(ctx context.Context, user db.User) (*pb.LoginUserResponse, error) {
+	sessionId := uuid.New()
+	token, tokenPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
+		user.Role,
+		sessionId,
+		utils.TokenTypeAccess,
 		server.config.TokenDuration,
-		server.config.Secret,
 	)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create token: %s", err)
 	}
-	refreshTokenId, refreshToken, refreshToken_expiration, err = utils.GenerateToken(
+	refreshToken, refreshTokenPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
+		user.Role,
+		sessionId,
+		utils.TokenTypeRefresh,
 		server.config.RefereshTokenDuration,
-		server.config.Secret,
 	)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create refresh token: %s", err)
@@ -55,13 +109,16 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 
 	mtdt := server.extractMetadata(ctx)
 	SessionParams := db.CreateSessionParams{
-		ID:           refreshTokenId,
+		ID:           sessionId,
 		Username:     user.Username,
 		RefreshToken: refreshToken,
 		UserAgent:    mtdt.UserAgent,
 		ClientIp:     mtdt.ClientIP,
 		IsBlocked:    false,
-		ExpiresAt:    refreshToken_expiration,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  refreshTokenPayload.ExpiredAt,
+			Valid: true,
+		},
 	}
 	session, err := server.store.CreateSession(ctx, SessionParams)
 	// fmt.Printf("sessionParams: %+v\n", SessionParams)
@@ -75,12 +132,14 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 			Email:             user.Email,
 			PasswordChangedAt: timestamppb.New(user.PasswordChangedAt.Time),
 			CreatedAt:         timestamppb.New(user.CreatedAt.Time),
+			Role:              user.Role,
+			Locale:            user.Locale,
 		},
 		SessionId:             session.ID.String(),
 		AccessToken:           token,
 		RefreshToken:          refreshToken,
-		AccessTokenExpiresAt:  timestamppb.New(token_expiration.Time),
-		RefreshTokenExpiresAt: timestamppb.New(refreshToken_expiration.Time),
+		AccessTokenExpiresAt:  timestamppb.New(tokenPayload.ExpiredAt),
+		RefreshTokenExpiresAt: timestamppb.New(refreshTokenPayload.ExpiredAt),
 	}, nil
 }
 
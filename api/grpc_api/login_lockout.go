@@ -0,0 +1,153 @@
+package grpc_api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/absk07/Go-Bank/worker"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxFailedLogins is how many failed logins in a row lock a user out. Every further failed login,
+	// once the lockout is over, locks the user out again for twice as long.
+	maxFailedLogins      = 5
+	loginLockoutDuration = time.Minute
+	maxLoginLockout      = 24 * time.Hour
+
+	// maxFailedLoginsPerClientIP limits the guesses a client can make across every username
+	maxFailedLoginsPerClientIP = 50
+	failedLoginsWindow         = 15 * time.Minute
+)
+
+// loginLockoutFor returns how long a user is locked out after failedAttempts failed logins in a row
+func loginLockoutFor(failedAttempts int32) time.Duration {
+	if failedAttempts < maxFailedLogins {
+		return 0
+	}
+	lockout := loginLockoutDuration
+	for i := int32(maxFailedLogins); i < failedAttempts && lockout < maxLoginLockout; i++ {
+		lockout *= 2
+	}
+	return min(lockout, maxLoginLockout)
+}
+
+// loginClientIP returns the ip failed logins are counted by, without the port of the peer address
+func loginClientIP(mtdt *Metadata) string {
+	if host, _, err := net.SplitHostPort(mtdt.ClientIP); err == nil {
+		return host
+	}
+	return mtdt.ClientIP
+}
+
+// checkLoginThrottle rejects logins from a client that failed too many logins recently
+func (server *Server) checkLoginThrottle(ctx context.Context, clientIP string) error {
+	if clientIP == "" {
+		return nil
+	}
+	failedLogins, err := server.store.CountFailedLoginsByClientIp(ctx, db.CountFailedLoginsByClientIpParams{
+		ClientIp: clientIP,
+		Since: pgtype.Timestamptz{
+			Time:  time.Now().Add(-failedLoginsWindow),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to count failed logins: %s", err)
+	}
+	if failedLogins >= maxFailedLoginsPerClientIP {
+		return status.Errorf(codes.ResourceExhausted, "too many failed logins, try again later")
+	}
+	return nil
+}
+
+// checkLoginLockout rejects logins of a user that is locked out, before its password is checked
+func checkLoginLockout(user db.User) error {
+	if user.LockedUntil.Valid && time.Now().Before(user.LockedUntil.Time) {
+		return status.Errorf(codes.PermissionDenied, "account is locked after too many failed logins, try again after %s", user.LockedUntil.Time.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// recordFailedLogin counts a failed login against the username and the client, and emails the user
+// when it gets locked out
+func (server *Server) recordFailedLogin(ctx context.Context, username string, clientIP string) error {
+	_, err := server.store.RecordFailedLoginTx(ctx, db.RecordFailedLoginTxParams{
+		CreateFailedLoginParams: db.CreateFailedLoginParams{
+			Username: username,
+			ClientIp: clientIP,
+		},
+		LockDuration: loginLockoutFor,
+		AfterLock: func(q db.Querier, user db.User) error {
+			taskPayload := &worker.PayloadSendAccountLocked{
+				Username:    user.Username,
+				LockedUntil: user.LockedUntil.Time,
+			}
+			opts := []asynq.Option{
+				asynq.MaxRetry(10),
+				asynq.Queue(worker.QueueCrirical),
+			}
+			return worker.NewOutboxTaskDistributor(q).DistributeTaskSendAccountLocked(ctx, taskPayload, opts...)
+		},
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to record failed login: %s", err)
+	}
+	return nil
+}
+
+// resetFailedLogins starts the count of failed logins of a user over, once the user has logged in
+func (server *Server) resetFailedLogins(ctx context.Context, user db.User) (db.User, error) {
+	if user.FailedLoginAttempts == 0 {
+		return user, nil
+	}
+	user, err := server.store.UnlockUser(ctx, user.Username)
+	if err != nil {
+		return user, status.Errorf(codes.Internal, "failed to reset failed logins: %s", err)
+	}
+	return user, nil
+}
+
+func (server *Server) UnlockUser(ctx context.Context, req *pb.UnlockUserRequest) (*pb.UnlockUserResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+	if err := requireRole(payload, utils.AdminRole); err != nil {
+		return nil, err
+	}
+
+	violations := validateUnlockUserRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	user, err := server.store.UnlockUser(ctx, req.GetUsername())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to unlock user: %s", err)
+	}
+
+	return &pb.UnlockUserResponse{
+		User: convertUser(user),
+	}, nil
+}
+
+func validateUnlockUserRequest(req *pb.UnlockUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, helpers.FieldViolation("username", err))
+	}
+	return violations
+}
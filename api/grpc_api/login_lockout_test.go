@@ -0,0 +1,123 @@
+package grpc_api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/absk07/Go-Bank/worker"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoginLockout(t *testing.T) {
+	store := db.NewMemoryStore()
+	server := newTestServer(t, store)
+	createTestUser(t, store, "alice", "secret123")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-for", "203.0.113.1"))
+
+	for i := 0; i < maxFailedLogins-1; i++ {
+		_, err := server.LoginUser(ctx, &pb.LoginUserRequest{Username: "alice", Password: "wrong123"})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("wrong password got %v, want %s", err, codes.NotFound)
+		}
+	}
+	// a successful login starts the count over
+	if _, err := server.LoginUser(ctx, &pb.LoginUserRequest{Username: "alice", Password: "secret123"}); err != nil {
+		t.Fatal(err)
+	}
+	user, err := store.GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.FailedLoginAttempts != 0 {
+		t.Fatalf("%d failed attempts after a successful login, want 0", user.FailedLoginAttempts)
+	}
+
+	for i := 0; i < maxFailedLogins; i++ {
+		server.LoginUser(ctx, &pb.LoginUserRequest{Username: "alice", Password: "wrong123"})
+	}
+	_, err = server.LoginUser(ctx, &pb.LoginUserRequest{Username: "alice", Password: "secret123"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("login of a locked out user got %v, want %s", err, codes.PermissionDenied)
+	}
+	user, err = store.GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lockout := time.Until(user.LockedUntil.Time); lockout <= 0 || lockout > loginLockoutDuration {
+		t.Fatalf("locked out for %s, want up to %s", lockout, loginLockoutDuration)
+	}
+	if countOutboxTasks(t, store, worker.TaskSendAccountLocked) != 1 {
+		t.Fatal("user was not told about the lockout")
+	}
+
+	// failing again once the lockout is over locks the user out for twice as long
+	_, err = store.LockUser(context.Background(), db.LockUserParams{
+		Username:    "alice",
+		LockedUntil: pgtype.Timestamptz{Time: time.Now().Add(-time.Second), Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.LoginUser(ctx, &pb.LoginUserRequest{Username: "alice", Password: "wrong123"})
+	user, err = store.GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lockout := time.Until(user.LockedUntil.Time); lockout <= loginLockoutDuration {
+		t.Fatalf("locked out again for %s, want more than %s", lockout, loginLockoutDuration)
+	}
+
+	// an admin lifts the lockout
+	_, err = server.UnlockUser(authContext("alice", utils.DepositorRole), &pb.UnlockUserRequest{Username: "alice"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("unlocking as a depositor got %v, want %s", err, codes.PermissionDenied)
+	}
+	if _, err := server.UnlockUser(authContext("root", utils.AdminRole), &pb.UnlockUserRequest{Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.LoginUser(ctx, &pb.LoginUserRequest{Username: "alice", Password: "secret123"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoginThrottlePerClientIP(t *testing.T) {
+	store := db.NewMemoryStore()
+	server := newTestServer(t, store)
+	createTestUser(t, store, "alice", "secret123")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-for", "203.0.113.1"))
+
+	// failures spread over usernames that don't exist still count against the client
+	for i := 0; i < maxFailedLoginsPerClientIP; i++ {
+		server.LoginUser(ctx, &pb.LoginUserRequest{Username: "ghost", Password: "wrong123"})
+	}
+	_, err := server.LoginUser(ctx, &pb.LoginUserRequest{Username: "alice", Password: "secret123"})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("login from a throttled client got %v, want %s", err, codes.ResourceExhausted)
+	}
+
+	other := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-for", "203.0.113.2"))
+	if _, err := server.LoginUser(other, &pb.LoginUserRequest{Username: "alice", Password: "secret123"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func countOutboxTasks(t *testing.T, store db.Store, taskType string) int {
+	messages, err := store.ListUnsentOutboxMessages(context.Background(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for _, message := range messages {
+		if message.TaskType == taskType {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,69 @@
+package grpc_api
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := utils.Config{
+		Secret:                  "0123456789abcdef0123456789abcdef",
+		SigningKeyEncryptionKey: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+		TokenDuration:           time.Minute,
+		RefereshTokenDuration:   time.Hour,
+	}
+	server, err := NewServer(config, store)
+	if err != nil {
+		t.Fatalf("cannot create server: %s", err)
+	}
+	return server
+}
+
+// authContext returns the context of a request the interceptor has already authenticated as username
+func authContext(username string, role string, mtdt ...string) context.Context {
+	payload := utils.NewPayload(username, role, uuid.New(), utils.TokenTypeAccess, time.Minute)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(mtdt...))
+	return context.WithValue(ctx, principalKey{}, payload)
+}
+
+func createTestUser(t *testing.T, store db.Store, username string, password string) db.User {
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := store.CreateUser(context.Background(), db.CreateUserParams{
+		Username: username,
+		Password: hashedPassword,
+		Fullname: username,
+		Email:    username + "@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func createTestAccount(t *testing.T, store db.Store, owner string, balance int64) db.Account {
+	account, err := store.CreateAccount(context.Background(), db.CreateAccountParams{
+		Owner:    owner,
+		Balance:  balance,
+		Currency: utils.USD,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return account
+}
@@ -2,8 +2,11 @@ package grpc_api
 
 import (
 	"context"
+	"net/textproto"
+	"strings"
 	// "log"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
@@ -12,6 +15,8 @@ const (
 	grpcGatewayUserAgentHeader = "grpcgateway-user-agent"
 	userAgentHeader            = "user-agent"
 	xForwardedForHeader        = "x-forwarded-for"
+	idempotencyKeyHeader       = "idempotency-key"
+	contentDispositionHeader   = "content-disposition"
 )
 
 type Metadata struct {
@@ -30,7 +35,7 @@ func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 			mtdt.UserAgent = userAgents[0]
 		}
 		if clientIPs := md.Get(xForwardedForHeader); len(clientIPs) > 0 {
-			mtdt.ClientIP = clientIPs[0]
+			mtdt.ClientIP = lastForwardedFor(clientIPs)
 		}
 	}
 	if p, ok := peer.FromContext(ctx); ok {
@@ -38,4 +43,41 @@ func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 	}
 
 	return mtdt
-}
\ No newline at end of file
+}
+
+// lastForwardedFor returns the last hop of the X-Forwarded-For values, which the gateway appends with
+// the address the request came from. The hops before it are sent by the client, who can make them up.
+func lastForwardedFor(values []string) string {
+	hops := strings.Split(values[len(values)-1], ",")
+	return strings.TrimSpace(hops[len(hops)-1])
+}
+
+// extractIdempotencyKey returns the Idempotency-Key sent with the request, if any
+func (server *Server) extractIdempotencyKey(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(idempotencyKeyHeader); len(keys) > 0 {
+			return keys[0]
+		}
+	}
+	return ""
+}
+
+// HeaderMatcher forwards the HTTP headers the handlers read from gRPC metadata
+// through the gateway, on top of the ones runtime.DefaultHeaderMatcher forwards
+func HeaderMatcher(key string) (string, bool) {
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case "Idempotency-Key":
+		return idempotencyKeyHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// OutgoingHeaderMatcher passes the response headers set by the handlers through the gateway
+// as plain HTTP headers, the rest keep the Grpc-Metadata- prefix
+func OutgoingHeaderMatcher(key string) (string, bool) {
+	switch key {
+	case contentDispositionHeader:
+		return textproto.CanonicalMIMEHeaderKey(key), true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
@@ -0,0 +1,78 @@
+package grpc_api
+
+import (
+	"context"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListNotificationPreferences(ctx context.Context, req *pb.ListNotificationPreferencesRequest) (*pb.ListNotificationPreferencesResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	preferences, err := server.store.ListNotificationPreferences(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list notification preferences: %s", err)
+	}
+
+	// a channel the user never changed has every notification on
+	stored := make(map[string]db.NotificationPreference, len(preferences))
+	for _, preference := range preferences {
+		stored[preference.Channel] = preference
+	}
+	rsp := &pb.ListNotificationPreferencesResponse{
+		Preferences: make([]*pb.NotificationPreference, 0, len(utils.NotificationChannels)),
+	}
+	for _, channel := range utils.NotificationChannels {
+		preference, ok := stored[channel]
+		if !ok {
+			preference = db.NotificationPreference{
+				Username:              payload.Username,
+				Channel:               channel,
+				TransferNotifications: true,
+			}
+		}
+		rsp.Preferences = append(rsp.Preferences, convertNotificationPreference(preference))
+	}
+	return rsp, nil
+}
+
+func (server *Server) UpdateNotificationPreference(ctx context.Context, req *pb.UpdateNotificationPreferenceRequest) (*pb.UpdateNotificationPreferenceResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateUpdateNotificationPreferenceRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	preference, err := server.store.UpsertNotificationPreference(ctx, db.UpsertNotificationPreferenceParams{
+		Username:              payload.Username,
+		Channel:               req.GetChannel(),
+		TransferNotifications: req.GetTransferNotifications(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update notification preference: %s", err)
+	}
+
+	return &pb.UpdateNotificationPreferenceResponse{
+		Preference: convertNotificationPreference(preference),
+	}, nil
+}
+
+func validateUpdateNotificationPreferenceRequest(req *pb.UpdateNotificationPreferenceRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateNotificationChannel(req.GetChannel()); err != nil {
+		violations = append(violations, helpers.FieldViolation("channel", err))
+	}
+	return violations
+}
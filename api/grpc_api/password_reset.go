@@ -0,0 +1,172 @@
+package grpc_api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/absk07/Go-Bank/worker"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const passwordResetMessage = "if the email belongs to a user, a password reset link has been sent to it"
+
+const (
+	// a client can request this many password resets per window, for any emails
+	maxPasswordResetRequestsPerClientIP = 10
+	passwordResetRequestsWindow         = time.Hour
+	// passwordResetCooldown is how long after a link is sent to an email before another one can be sent to it
+	passwordResetCooldown = 2 * time.Minute
+)
+
+func (server *Server) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	violations := validateRequestPasswordResetRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	clientIP := loginClientIP(server.extractMetadata(ctx))
+	if err := server.checkPasswordResetThrottle(ctx, clientIP); err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.RequestPasswordResetResponse{
+		Message: passwordResetMessage,
+	}
+
+	// an unknown email gets the same response, so that it cannot be used to find the emails of users
+	user, err := server.store.GetUserByEmail(ctx, req.GetEmail())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			if err := server.recordPasswordResetRequest(ctx, req.GetEmail(), clientIP, false); err != nil {
+				return nil, err
+			}
+			return rsp, nil
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+
+	// so does an email a link was just sent to, so that requests can't flood the inbox of a user
+	sentLinks, err := server.store.CountSentPasswordResetRequests(ctx, db.CountSentPasswordResetRequestsParams{
+		Email: user.Email,
+		Since: pgtype.Timestamptz{
+			Time:  time.Now().Add(-passwordResetCooldown),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count password reset requests: %s", err)
+	}
+	if sentLinks > 0 {
+		if err := server.recordPasswordResetRequest(ctx, user.Email, clientIP, false); err != nil {
+			return nil, err
+		}
+		return rsp, nil
+	}
+	if err := server.recordPasswordResetRequest(ctx, user.Email, clientIP, true); err != nil {
+		return nil, err
+	}
+
+	taskPayload := &worker.PayloadSendPasswordReset{
+		Username: user.Username,
+	}
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueCrirical),
+	}
+	err = worker.NewOutboxTaskDistributor(server.store).DistributeTaskSendPasswordReset(ctx, taskPayload, opts...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to send password reset email: %s", err)
+	}
+
+	return rsp, nil
+}
+
+// checkPasswordResetThrottle rejects password reset requests from a client that made too many recently
+func (server *Server) checkPasswordResetThrottle(ctx context.Context, clientIP string) error {
+	if clientIP == "" {
+		return nil
+	}
+	requests, err := server.store.CountPasswordResetRequestsByClientIp(ctx, db.CountPasswordResetRequestsByClientIpParams{
+		ClientIp: clientIP,
+		Since: pgtype.Timestamptz{
+			Time:  time.Now().Add(-passwordResetRequestsWindow),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to count password reset requests: %s", err)
+	}
+	if requests >= maxPasswordResetRequestsPerClientIP {
+		return status.Errorf(codes.ResourceExhausted, "too many password reset requests, try again later")
+	}
+	return nil
+}
+
+func (server *Server) recordPasswordResetRequest(ctx context.Context, email string, clientIP string, linkSent bool) error {
+	_, err := server.store.CreatePasswordResetRequest(ctx, db.CreatePasswordResetRequestParams{
+		Email:    email,
+		ClientIp: clientIP,
+		LinkSent: linkSent,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to record password reset request: %s", err)
+	}
+	return nil
+}
+
+func validateRequestPasswordResetRequest(req *pb.RequestPasswordResetRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateEmail(req.GetEmail()); err != nil {
+		violations = append(violations, helpers.FieldViolation("email", err))
+	}
+	return violations
+}
+
+func (server *Server) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	violations := validateResetPasswordRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	hashedPassword, err := utils.HashPassword(req.GetPassword())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
+	}
+
+	_, err = server.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		ResetId:        req.GetResetId(),
+		SecretCode:     req.GetSecretCode(),
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.PermissionDenied, "password reset link is invalid or has expired")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reset password: %s", err)
+	}
+
+	return &pb.ResetPasswordResponse{
+		IsReset: true,
+	}, nil
+}
+
+func validateResetPasswordRequest(req *pb.ResetPasswordRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateEmailId(req.GetResetId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("reset_id", err))
+	}
+	if err := utils.ValidateSecretCode(req.GetSecretCode()); err != nil {
+		violations = append(violations, helpers.FieldViolation("secret_code", err))
+	}
+	if err := utils.ValidatePassword(req.GetPassword()); err != nil {
+		violations = append(violations, helpers.FieldViolation("password", err))
+	}
+	return violations
+}
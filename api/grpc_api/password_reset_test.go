@@ -0,0 +1,71 @@
+package grpc_api
+
+import (
+	"context"
+	"testing"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/worker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestPasswordResetCooldown(t *testing.T) {
+	store := db.NewMemoryStore()
+	server := newTestServer(t, store)
+	user := createTestUser(t, store, "alice", "secret123")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-for", "203.0.113.1"))
+
+	for i := 0; i < 3; i++ {
+		rsp, err := server.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: user.Email})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rsp.Message != passwordResetMessage {
+			t.Fatalf("got message %q", rsp.Message)
+		}
+	}
+	// only the first request sends a link, the others are within its cooldown
+	if sent := countOutboxTasks(t, store, worker.TaskSendPasswordReset); sent != 1 {
+		t.Fatalf("sent %d password reset links, want 1", sent)
+	}
+
+	// an unknown email looks the same
+	rsp, err := server.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: "nobody@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.Message != passwordResetMessage {
+		t.Fatalf("got message %q", rsp.Message)
+	}
+}
+
+func TestRequestPasswordResetThrottlePerClientIP(t *testing.T) {
+	store := db.NewMemoryStore()
+	server := newTestServer(t, store)
+	user := createTestUser(t, store, "alice", "secret123")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-for", "203.0.113.1"))
+
+	for i := 0; i < maxPasswordResetRequestsPerClientIP; i++ {
+		if _, err := server.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: "nobody@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := server.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: user.Email})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("request from a throttled client got %v, want %s", err, codes.ResourceExhausted)
+	}
+	if sent := countOutboxTasks(t, store, worker.TaskSendPasswordReset); sent != 0 {
+		t.Fatalf("sent %d password reset links to a throttled client", sent)
+	}
+
+	other := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-for", "203.0.113.2"))
+	if _, err := server.RequestPasswordReset(other, &pb.RequestPasswordResetRequest{Email: user.Email}); err != nil {
+		t.Fatal(err)
+	}
+	if sent := countOutboxTasks(t, store, worker.TaskSendPasswordReset); sent != 1 {
+		t.Fatalf("sent %d password reset links, want 1", sent)
+	}
+}
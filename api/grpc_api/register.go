@@ -2,7 +2,6 @@ package grpc_api
 
 import (
 	"context"
-	"time"
 
 	db "github.com/absk07/Go-Bank/db/sqlc"
 	"github.com/absk07/Go-Bank/helpers"
@@ -27,23 +26,28 @@ func (server *Server) RegisterUser(ctx context.Context, req *pb.RegisterUserRequ
 		return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
 	}
 
+	locale := utils.DefaultLocale
+	if req.Locale != nil {
+		locale = req.GetLocale()
+	}
+
 	args := db.CreateUserTxParams{
 		CreateUserParams: db.CreateUserParams{
 			Username: req.GetUsername(),
 			Password: hashedPassword,
 			Fullname: req.GetFullName(),
 			Email:    req.GetEmail(),
+			Locale:   locale,
 		},
-		AfterCreate: func(user db.User) error {
+		AfterCreate: func(q db.Querier, user db.User) error {
 			taskPayload := &worker.PayloadSendVerifyEmail{
 				Username: user.Username,
 			}
 			opts := []asynq.Option{
 				asynq.MaxRetry(10),
-				asynq.ProcessIn(10 * time.Second),
 				asynq.Queue(worker.QueueCrirical),
 			}
-			return server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
+			return worker.NewOutboxTaskDistributor(q).DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
 		},
 	}
 	txResult, err := server.store.CreateUserTx(ctx, args)
@@ -58,6 +62,8 @@ func (server *Server) RegisterUser(ctx context.Context, req *pb.RegisterUserRequ
 			Email:             txResult.User.Email,
 			PasswordChangedAt: timestamppb.New(txResult.User.PasswordChangedAt.Time),
 			CreatedAt:         timestamppb.New(txResult.User.CreatedAt.Time),
+			Role:              txResult.User.Role,
+			Locale:            txResult.User.Locale,
 		},
 	}, nil
 }
@@ -75,5 +81,10 @@ func validateCreateUserRequest(req *pb.RegisterUserRequest) (violations []*errde
 	if err := utils.ValidateEmail(req.GetEmail()); err != nil {
 		violations = append(violations, helpers.FieldViolation("email", err))
 	}
+	if req.Locale != nil {
+		if err := utils.ValidateLocale(req.GetLocale()); err != nil {
+			violations = append(violations, helpers.FieldViolation("locale", err))
+		}
+	}
 	return violations
 }
\ No newline at end of file
@@ -7,6 +7,8 @@ import (
 	"github.com/absk07/Go-Bank/pb"
 	"github.com/absk07/Go-Bank/helpers"
 	"github.com/absk07/Go-Bank/utils"
+	"github.com/absk07/Go-Bank/worker"
+	"github.com/hibiken/asynq"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -21,8 +23,31 @@ func (server *Server) VerifyEmail(ctx context.Context, req *pb.VerifyEmailReques
 	txResult, err := server.store.VerifyEmailTx(ctx, db.VerifyEmailTxParams{
 		EmailId:    req.GetEmailId(),
 		SecretCode: req.GetSecretCode(),
+		AfterEmailChange: func(q db.Querier, user db.User, previousEmail string) error {
+			// the previous email is told about the change, in case it was not made by its owner
+			taskPayload := &worker.PayloadSendEmailChanged{
+				Username:      user.Username,
+				PreviousEmail: previousEmail,
+			}
+			opts := []asynq.Option{
+				asynq.MaxRetry(10),
+				asynq.Queue(worker.QueueCrirical),
+			}
+			return worker.NewOutboxTaskDistributor(q).DistributeTaskSendEmailChanged(ctx, taskPayload, opts...)
+		},
+		AfterVerify: func(q db.Querier, user db.User) error {
+			data := worker.UserEventData{
+				Username: user.Username,
+				Email:    user.Email,
+			}
+			return worker.DistributeWebhookEvent(ctx, q, worker.NewOutboxTaskDistributor(q), utils.WebhookEventUserEmailVerified, data, user.Username)
+		},
 	})
 	if err != nil {
+		// the pending email was taken by another user since it was requested
+		if db.ErrorCode(err) == db.UniqueViolation {
+			return nil, status.Errorf(codes.AlreadyExists, "email is already in use")
+		}
 		return nil, status.Errorf(codes.Internal, "failed to verify email")
 	}
 
@@ -0,0 +1,353 @@
+package grpc_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateScheduledTransfer(ctx context.Context, req *pb.CreateScheduledTransferRequest) (*pb.CreateScheduledTransferResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	now := time.Now()
+	violations := validateCreateScheduledTransferRequest(req, now)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.validAccount(ctx, "from_account_id", req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+	if fromAccount.Owner != payload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+	toCurrency := req.GetCurrency()
+	if req.ToCurrency != nil {
+		toCurrency = req.GetToCurrency()
+	}
+	_, err = server.validAccount(ctx, "to_account_id", req.GetToAccountId(), toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	startAt := now
+	if req.StartTime != nil {
+		startAt = req.GetStartTime().AsTime()
+	}
+	// the first run is the first occurrence at or after the start time
+	nextRunAt, err := utils.NextRun(req.GetFrequency(), req.GetCronExpression(), startAt, startAt.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to compute next run: %s", err)
+	}
+	if req.GetFrequency() == utils.FrequencyCron {
+		startAt = nextRunAt
+	}
+
+	scheduledTransfer, err := server.store.CreateScheduledTransfer(ctx, db.CreateScheduledTransferParams{
+		Owner:          payload.Username,
+		FromAccountID:  fromAccount.ID,
+		ToAccountID:    req.GetToAccountId(),
+		Amount:         req.GetAmount(),
+		Frequency:      req.GetFrequency(),
+		CronExpression: req.GetCronExpression(),
+		StartAt:        pgtype.Timestamptz{Time: startAt, Valid: true},
+		NextRunAt:      pgtype.Timestamptz{Time: nextRunAt, Valid: true},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create scheduled transfer: %s", err)
+	}
+
+	return &pb.CreateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer),
+	}, nil
+}
+
+func validateCreateScheduledTransferRequest(req *pb.CreateScheduledTransferRequest, now time.Time) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateId(req.GetFromAccountId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("from_account_id", err))
+	}
+	if err := utils.ValidateId(req.GetToAccountId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("to_account_id", err))
+	}
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, helpers.FieldViolation("to_account_id", fmt.Errorf("must be different from from_account_id")))
+	}
+	if err := utils.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, helpers.FieldViolation("amount", err))
+	}
+	if err := utils.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, helpers.FieldViolation("currency", err))
+	}
+	if req.ToCurrency != nil {
+		if err := utils.ValidateCurrency(req.GetToCurrency()); err != nil {
+			violations = append(violations, helpers.FieldViolation("to_currency", err))
+		}
+	}
+	if err := utils.ValidateFrequency(req.GetFrequency()); err != nil {
+		violations = append(violations, helpers.FieldViolation("frequency", err))
+	}
+	if req.GetFrequency() == utils.FrequencyCron {
+		if err := utils.ValidateCronExpression(req.GetCronExpression()); err != nil {
+			violations = append(violations, helpers.FieldViolation("cron_expression", err))
+		}
+	} else if req.GetCronExpression() != "" {
+		violations = append(violations, helpers.FieldViolation("cron_expression", fmt.Errorf("must only be set with the %s frequency", utils.FrequencyCron)))
+	}
+	if req.StartTime != nil && req.GetStartTime().AsTime().Before(now) {
+		violations = append(violations, helpers.FieldViolation("start_time", fmt.Errorf("must not be in the past")))
+	}
+	return violations
+}
+
+func (server *Server) GetScheduledTransfer(ctx context.Context, req *pb.GetScheduledTransferRequest) (*pb.GetScheduledTransferResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateScheduledTransferId("id", req.GetId())
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	scheduledTransfer, err := server.getScheduledTransfer(ctx, req.GetId(), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer),
+	}, nil
+}
+
+func (server *Server) ListScheduledTransfers(ctx context.Context, req *pb.ListScheduledTransfersRequest) (*pb.ListScheduledTransfersResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateListScheduledTransfersRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	owner := payload.Username
+	if req.Owner != nil {
+		owner = req.GetOwner()
+	}
+	if !canView(payload, owner) {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot list other user's scheduled transfers")
+	}
+
+	args := db.ListScheduledTransfersParams{
+		Owner:  owner,
+		Limit:  req.GetPageSize(),
+		Offset: (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+	scheduledTransfers, err := server.store.ListScheduledTransfers(ctx, args)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list scheduled transfers: %s", err)
+	}
+
+	rsp := &pb.ListScheduledTransfersResponse{
+		ScheduledTransfers: make([]*pb.ScheduledTransfer, 0, len(scheduledTransfers)),
+	}
+	for _, scheduledTransfer := range scheduledTransfers {
+		rsp.ScheduledTransfers = append(rsp.ScheduledTransfers, convertScheduledTransfer(scheduledTransfer))
+	}
+	return rsp, nil
+}
+
+func validateListScheduledTransfersRequest(req *pb.ListScheduledTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidatePageId(req.GetPageId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_id", err))
+	}
+	if err := utils.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_size", err))
+	}
+	if req.Owner != nil {
+		if err := utils.ValidateUsername(req.GetOwner()); err != nil {
+			violations = append(violations, helpers.FieldViolation("owner", err))
+		}
+	}
+	return violations
+}
+
+func (server *Server) UpdateScheduledTransfer(ctx context.Context, req *pb.UpdateScheduledTransferRequest) (*pb.UpdateScheduledTransferResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateUpdateScheduledTransferRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	scheduledTransfer, err := server.getScheduledTransfer(ctx, req.GetId(), payload)
+	if err != nil {
+		return nil, err
+	}
+	if scheduledTransfer.Owner != payload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "scheduled transfer doesn't belong to the authenticated user")
+	}
+	if scheduledTransfer.Status != db.ScheduleStatusActive && scheduledTransfer.Status != db.ScheduleStatusPaused {
+		return nil, status.Errorf(codes.FailedPrecondition, "scheduled transfer is %s", scheduledTransfer.Status)
+	}
+
+	args := db.UpdateScheduledTransferParams{
+		ID: scheduledTransfer.ID,
+	}
+	if req.Amount != nil {
+		args.Amount = pgtype.Int8{Int64: req.GetAmount(), Valid: true}
+	}
+	if req.Status != nil {
+		args.Status = pgtype.Text{String: req.GetStatus(), Valid: true}
+		// resuming gives the schedule a fresh set of retries, a missed run is made at the next check
+		if req.GetStatus() == db.ScheduleStatusActive {
+			args.FailureCount = pgtype.Int4{Int32: 0, Valid: true}
+		}
+	}
+
+	scheduledTransfer, err = server.store.UpdateScheduledTransfer(ctx, args)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update scheduled transfer: %s", err)
+	}
+
+	return &pb.UpdateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer),
+	}, nil
+}
+
+func validateUpdateScheduledTransferRequest(req *pb.UpdateScheduledTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = validateScheduledTransferId("id", req.GetId())
+	if req.Amount != nil {
+		if err := utils.ValidateAmount(req.GetAmount()); err != nil {
+			violations = append(violations, helpers.FieldViolation("amount", err))
+		}
+	}
+	if req.Status != nil {
+		switch req.GetStatus() {
+		case db.ScheduleStatusActive, db.ScheduleStatusPaused:
+		default:
+			err := fmt.Errorf("must be either %s or %s", db.ScheduleStatusActive, db.ScheduleStatusPaused)
+			violations = append(violations, helpers.FieldViolation("status", err))
+		}
+	}
+	return violations
+}
+
+func (server *Server) DeleteScheduledTransfer(ctx context.Context, req *pb.DeleteScheduledTransferRequest) (*pb.DeleteScheduledTransferResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateScheduledTransferId("id", req.GetId())
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	scheduledTransfer, err := server.getScheduledTransfer(ctx, req.GetId(), payload)
+	if err != nil {
+		return nil, err
+	}
+	if scheduledTransfer.Owner != payload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "scheduled transfer doesn't belong to the authenticated user")
+	}
+
+	// the schedule is only cancelled, so that the history of its runs is kept
+	scheduledTransfer, err = server.store.UpdateScheduledTransfer(ctx, db.UpdateScheduledTransferParams{
+		ID:     scheduledTransfer.ID,
+		Status: pgtype.Text{String: db.ScheduleStatusCancelled, Valid: true},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete scheduled transfer: %s", err)
+	}
+
+	return &pb.DeleteScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer),
+	}, nil
+}
+
+func (server *Server) ListScheduledTransferRuns(ctx context.Context, req *pb.ListScheduledTransferRunsRequest) (*pb.ListScheduledTransferRunsResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateListScheduledTransferRunsRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	scheduledTransfer, err := server.getScheduledTransfer(ctx, req.GetScheduledTransferId(), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	args := db.ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduledTransfer.ID,
+		Limit:               req.GetPageSize(),
+		Offset:              (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+	runs, err := server.store.ListScheduledTransferRuns(ctx, args)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list scheduled transfer runs: %s", err)
+	}
+
+	rsp := &pb.ListScheduledTransferRunsResponse{
+		Runs: make([]*pb.ScheduledTransferRun, 0, len(runs)),
+	}
+	for _, run := range runs {
+		rsp.Runs = append(rsp.Runs, convertScheduledTransferRun(run))
+	}
+	return rsp, nil
+}
+
+func validateListScheduledTransferRunsRequest(req *pb.ListScheduledTransferRunsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = validateScheduledTransferId("scheduled_transfer_id", req.GetScheduledTransferId())
+	if err := utils.ValidatePageId(req.GetPageId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_id", err))
+	}
+	if err := utils.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_size", err))
+	}
+	return violations
+}
+
+func validateScheduledTransferId(field string, id int64) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateId(id); err != nil {
+		violations = append(violations, helpers.FieldViolation(field, err))
+	}
+	return violations
+}
+
+// getScheduledTransfer returns a scheduled transfer if the authenticated user can view it
+func (server *Server) getScheduledTransfer(ctx context.Context, id int64, payload *utils.Payload) (db.ScheduledTransfer, error) {
+	scheduledTransfer, err := server.store.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return scheduledTransfer, status.Errorf(codes.NotFound, "scheduled transfer not found")
+		}
+		return scheduledTransfer, status.Errorf(codes.Internal, "failed to get scheduled transfer: %s", err)
+	}
+	if !canView(payload, scheduledTransfer.Owner) {
+		return scheduledTransfer, status.Errorf(codes.PermissionDenied, "scheduled transfer doesn't belong to the authenticated user")
+	}
+	return scheduledTransfer, nil
+}
@@ -1,24 +1,37 @@
 package grpc_api
 
 import (
+	"fmt"
+
 	db "github.com/absk07/Go-Bank/db/sqlc"
 	"github.com/absk07/Go-Bank/pb"
 	"github.com/absk07/Go-Bank/utils"
-	"github.com/absk07/Go-Bank/worker"
 )
 
 type Server struct {
 	pb.UnimplementedGoBankServer 
 	config utils.Config
-	store *db.Store
-	taskDistributor worker.TaskDistributor
+	store db.Store
+	tokenMaker utils.TokenMaker
+	keyring *utils.Keyring
+	keyCipher *utils.SigningKeyCipher
 }
 
-func NewServer(config utils.Config, store *db.Store, taskDistributor worker.TaskDistributor) *Server {
+func NewServer(config utils.Config, store db.Store) (*Server, error) {
+	keyCipher, err := utils.NewSigningKeyCipher(config.SigningKeyEncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create signing key cipher: %w", err)
+	}
+	keyring, err := db.NewSigningKeyring(config, store, keyCipher)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
 	server := &Server{
 		config: config,
 		store: store,
-		taskDistributor: taskDistributor,
+		tokenMaker: keyring,
+		keyring: keyring,
+		keyCipher: keyCipher,
 	}
-	return server
+	return server, nil
 }
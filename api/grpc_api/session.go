@@ -0,0 +1,125 @@
+package grpc_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	_, err = server.store.BlockSession(ctx, payload.SessionID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to block session: %s", err)
+	}
+
+	return &pb.LogoutResponse{}, nil
+}
+
+func (server *Server) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateListSessionsRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	args := db.ListSessionsParams{
+		Username: payload.Username,
+		Limit:    req.GetPageSize(),
+		Offset:   (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+	sessions, err := server.store.ListSessions(ctx, args)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list sessions: %s", err)
+	}
+
+	rsp := &pb.ListSessionsResponse{
+		Sessions: make([]*pb.Session, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		rsp.Sessions = append(rsp.Sessions, convertSession(session, payload.SessionID))
+	}
+	return rsp, nil
+}
+
+func validateListSessionsRequest(req *pb.ListSessionsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidatePageId(req.GetPageId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_id", err))
+	}
+	if err := utils.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_size", err))
+	}
+	return violations
+}
+
+func (server *Server) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	sessionId, err := uuid.Parse(req.GetSessionId())
+	if err != nil {
+		violations := []*errdetails.BadRequest_FieldViolation{
+			helpers.FieldViolation("session_id", fmt.Errorf("must be a valid uuid")),
+		}
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	session, err := server.store.GetSession(ctx, sessionId)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "session not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get session: %s", err)
+	}
+	// another user's session is reported as missing so its id is not leaked
+	if session.Username != payload.Username {
+		return nil, status.Errorf(codes.NotFound, "session not found")
+	}
+
+	session, err = server.store.BlockSession(ctx, sessionId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to block session: %s", err)
+	}
+
+	return &pb.RevokeSessionResponse{
+		Session: convertSession(session, payload.SessionID),
+	}, nil
+}
+
+func (server *Server) RevokeAllSessions(ctx context.Context, req *pb.RevokeAllSessionsRequest) (*pb.RevokeAllSessionsResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	revokedCount, err := server.store.BlockUserSessions(ctx, db.BlockUserSessionsParams{
+		Username: payload.Username,
+		ExceptID: payload.SessionID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to block sessions: %s", err)
+	}
+
+	return &pb.RevokeAllSessionsResponse{
+		RevokedCount: revokedCount,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package grpc_api
+
+import (
+	"context"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) RotateSigningKey(ctx context.Context, req *pb.RotateSigningKeyRequest) (*pb.RotateSigningKeyResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+	if err := requireRole(payload, utils.AdminRole); err != nil {
+		return nil, err
+	}
+
+	signingKey, err := utils.GenerateSigningKey(server.keyring.Algorithm())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate signing key: %s", err)
+	}
+	sealedKey, err := server.keyCipher.Seal(signingKey.Kid, signingKey.Key)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encrypt signing key: %s", err)
+	}
+
+	// the old keys retire once every refresh token they signed has expired, including
+	// those signed by servers that have not loaded the new key yet
+	result, err := server.store.RotateSigningKeyTx(ctx, db.RotateSigningKeyTxParams{
+		CreateSigningKeyParams: db.CreateSigningKeyParams{
+			Kid:       signingKey.Kid,
+			Algorithm: signingKey.Algorithm,
+			Key:       sealedKey,
+		},
+		RetiresAt: time.Now().Add(server.config.RefereshTokenDuration + utils.KeyringRefreshInterval),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to rotate signing key: %s", err)
+	}
+
+	if err := server.keyring.Reload(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reload signing keys: %s", err)
+	}
+
+	rsp := &pb.RotateSigningKeyResponse{
+		SigningKey:  convertSigningKey(result.SigningKey),
+		RetiredKeys: make([]*pb.SigningKey, 0, len(result.RetiredKeys)),
+	}
+	for _, retiredKey := range result.RetiredKeys {
+		rsp.RetiredKeys = append(rsp.RetiredKeys, convertSigningKey(retiredKey))
+	}
+	return rsp, nil
+}
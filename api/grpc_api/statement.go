@@ -0,0 +1,187 @@
+package grpc_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	statementFormatCSV  = "csv"
+	statementFormatJSON = "json"
+	maxStatementPeriod  = 366 * 24 * time.Hour
+)
+
+func (server *Server) GetAccountStatement(ctx context.Context, req *pb.GetAccountStatementRequest) (*httpbody.HttpBody, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateGetAccountStatementRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if !canView(payload, account.Owner) {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	txResult, err := server.store.StatementTx(ctx, db.StatementTxParams{
+		AccountID: account.ID,
+		StartTime: req.GetStartTime().AsTime(),
+		EndTime:   req.GetEndTime().AsTime(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account statement: %s", err)
+	}
+
+	statement := convertStatement(req, txResult)
+
+	format := req.GetFormat()
+	if format == "" {
+		format = statementFormatJSON
+	}
+
+	var body *httpbody.HttpBody
+	switch format {
+	case statementFormatCSV:
+		body, err = statementCSV(statement)
+	default:
+		body, err = statementJSON(statement)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to render account statement: %s", err)
+	}
+
+	filename := fmt.Sprintf("statement-%d-%s-%s.%s",
+		account.ID,
+		statement.StartTime.AsTime().Format("20060102"),
+		statement.EndTime.AsTime().Format("20060102"),
+		format,
+	)
+	err = grpc.SetHeader(ctx, metadata.Pairs(contentDispositionHeader, fmt.Sprintf("attachment; filename=%q", filename)))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set response header: %s", err)
+	}
+
+	return body, nil
+}
+
+func convertStatement(req *pb.GetAccountStatementRequest, result db.StatementTxResult) *pb.AccountStatement {
+	lines := make([]*pb.StatementLine, 0, len(result.Lines))
+	for _, line := range result.Lines {
+		lines = append(lines, &pb.StatementLine{
+			Entry:   convertEntry(line.Entry),
+			Balance: line.Balance,
+		})
+	}
+
+	return &pb.AccountStatement{
+		Account:        convertAccount(result.Account),
+		StartTime:      req.GetStartTime(),
+		EndTime:        req.GetEndTime(),
+		OpeningBalance: result.OpeningBalance,
+		ClosingBalance: result.ClosingBalance,
+		Lines:          lines,
+	}
+}
+
+func statementJSON(statement *pb.AccountStatement) (*httpbody.HttpBody, error) {
+	data, err := protojson.MarshalOptions{
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+	}.Marshal(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpbody.HttpBody{
+		ContentType: "application/json",
+		Data:        data,
+	}, nil
+}
+
+// statementCSV renders one row per entry, framed by the opening and closing balance rows
+func statementCSV(statement *pb.AccountStatement) (*httpbody.HttpBody, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	formatTime := func(t *timestamppb.Timestamp) string {
+		return t.AsTime().UTC().Format(time.RFC3339)
+	}
+
+	records := [][]string{
+		{"date", "description", "entry_id", "amount", "balance"},
+		{formatTime(statement.GetStartTime()), "opening balance", "", "", strconv.FormatInt(statement.GetOpeningBalance(), 10)},
+	}
+	for _, line := range statement.GetLines() {
+		records = append(records, []string{
+			formatTime(line.GetEntry().GetCreatedAt()),
+			"entry",
+			strconv.FormatInt(line.GetEntry().GetId(), 10),
+			strconv.FormatInt(line.GetEntry().GetAmount(), 10),
+			strconv.FormatInt(line.GetBalance(), 10),
+		})
+	}
+	records = append(records, []string{
+		formatTime(statement.GetEndTime()), "closing balance", "", "", strconv.FormatInt(statement.GetClosingBalance(), 10),
+	})
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+
+	return &httpbody.HttpBody{
+		ContentType: "text/csv",
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+func validateGetAccountStatementRequest(req *pb.GetAccountStatementRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateId(req.GetAccountId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("account_id", err))
+	}
+	if req.StartTime == nil {
+		violations = append(violations, helpers.FieldViolation("start_time", fmt.Errorf("must be set")))
+	}
+	if req.EndTime == nil {
+		violations = append(violations, helpers.FieldViolation("end_time", fmt.Errorf("must be set")))
+	}
+	if req.StartTime != nil && req.EndTime != nil {
+		if err := utils.ValidateStatementPeriod(req.GetStartTime().AsTime(), req.GetEndTime().AsTime(), maxStatementPeriod); err != nil {
+			violations = append(violations, helpers.FieldViolation("end_time", err))
+		}
+	}
+	switch req.GetFormat() {
+	case "", statementFormatCSV, statementFormatJSON:
+	default:
+		violations = append(violations, helpers.FieldViolation("format", fmt.Errorf("must be either %s or %s", statementFormatCSV, statementFormatJSON)))
+	}
+	return violations
+}
@@ -0,0 +1,121 @@
+package grpc_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (server *Server) RenewAccessToken(ctx context.Context, req *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	violations := validateRenewAccessTokenRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(req.GetRefreshToken())
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(fmt.Errorf("invalid refresh token: %s", err))
+	}
+	// an access token is turned away before the session is read, so presenting one can't block the session
+	if payload.Type != utils.TokenTypeRefresh {
+		return nil, helpers.UnauthenticatedError(fmt.Errorf("invalid refresh token: not a refresh token"))
+	}
+
+	session, err := server.store.GetSession(ctx, payload.SessionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "session not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get session: %s", err)
+	}
+	if session.IsBlocked {
+		return nil, helpers.UnauthenticatedError(fmt.Errorf("session is blocked"))
+	}
+	if session.Username != payload.Username {
+		return nil, helpers.UnauthenticatedError(fmt.Errorf("incorrect session user"))
+	}
+	// a refresh token is only valid once. Seeing an older one again means it was stolen,
+	// so the session and every token issued for it is revoked.
+	if session.RefreshToken != req.GetRefreshToken() {
+		return nil, server.blockReusedSession(ctx, session)
+	}
+	if time.Now().After(session.ExpiresAt.Time) {
+		return nil, helpers.UnauthenticatedError(fmt.Errorf("expired session"))
+	}
+
+	// the role is read again, so that a role change applies from the next access token
+	user, err := server.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+
+	token, tokenPayload, err := server.tokenMaker.CreateToken(
+		user.Username,
+		user.Role,
+		session.ID,
+		utils.TokenTypeAccess,
+		server.config.TokenDuration,
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create token: %s", err)
+	}
+	refreshToken, refreshTokenPayload, err := server.tokenMaker.CreateToken(
+		user.Username,
+		user.Role,
+		session.ID,
+		utils.TokenTypeRefresh,
+		server.config.RefereshTokenDuration,
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create refresh token: %s", err)
+	}
+
+	// the swap only succeeds if the presented token is still the current one, so two
+	// concurrent renewals with the same token cannot both get a new refresh token
+	_, err = server.store.RotateSessionRefreshToken(ctx, db.RotateSessionRefreshTokenParams{
+		ID:              session.ID,
+		RefreshToken:    req.GetRefreshToken(),
+		NewRefreshToken: refreshToken,
+		ExpiresAt:       pgtype.Timestamptz{Time: refreshTokenPayload.ExpiredAt, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, server.blockReusedSession(ctx, session)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to rotate refresh token: %s", err)
+	}
+
+	return &pb.RenewAccessTokenResponse{
+		AccessToken:           token,
+		RefreshToken:          refreshToken,
+		AccessTokenExpiresAt:  timestamppb.New(tokenPayload.ExpiredAt),
+		RefreshTokenExpiresAt: timestamppb.New(refreshTokenPayload.ExpiredAt),
+	}, nil
+}
+
+// blockReusedSession revokes a session whose refresh token was presented after it had been rotated
+func (server *Server) blockReusedSession(ctx context.Context, session db.Session) error {
+	_, err := server.store.BlockSession(ctx, session.ID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to block session: %s", err)
+	}
+	return helpers.UnauthenticatedError(fmt.Errorf("refresh token reuse detected, session is blocked"))
+}
+
+func validateRenewAccessTokenRequest(req *pb.RenewAccessTokenRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetRefreshToken() == "" {
+		violations = append(violations, helpers.FieldViolation("refresh_token", fmt.Errorf("must not be empty")))
+	}
+	return violations
+}
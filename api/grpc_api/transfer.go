@@ -0,0 +1,287 @@
+package grpc_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/absk07/Go-Bank/worker"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	idempotencyKey := server.extractIdempotencyKey(ctx)
+	violations := validateCreateTransferRequest(req)
+	if idempotencyKey != "" {
+		if err := utils.ValidateIdempotencyKey(idempotencyKey); err != nil {
+			violations = append(violations, helpers.FieldViolation(idempotencyKeyHeader, err))
+		}
+	}
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.validAccount(ctx, "from_account_id", req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+	if fromAccount.Owner != payload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+	toCurrency := req.GetCurrency()
+	if req.ToCurrency != nil {
+		toCurrency = req.GetToCurrency()
+	}
+	_, err = server.validAccount(ctx, "to_account_id", req.GetToAccountId(), toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	args := db.TransferTxParams{
+		FromAccountId:  req.GetFromAccountId(),
+		ToAccountID:    req.GetToAccountId(),
+		Amount:         req.GetAmount(),
+		Username:       payload.Username,
+		IdempotencyKey: idempotencyKey,
+		AfterTransfer:  worker.AfterTransfer(ctx),
+	}
+	txResult, err := server.store.TransferTx(ctx, args)
+	if err != nil {
+		if errors.Is(err, db.ErrIdempotencyKeyReused) {
+			return nil, status.Errorf(codes.AlreadyExists, "%s", err)
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrNoFxRate) || errors.Is(err, db.ErrAmountTooSmall) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to transfer money: %s", err)
+	}
+
+	rsp := &pb.CreateTransferResponse{
+		Transfer:    convertTransfer(txResult.Transfer),
+		FromAccount: convertAccount(txResult.FromAccount),
+		ToAccount:   convertAccount(txResult.ToAccount),
+		FromEntry:   convertEntry(txResult.FromEntry),
+		ToEntry:     convertEntry(txResult.ToEntry),
+	}
+	if txResult.SettlementTransfer != nil {
+		rsp.SettlementTransfer = convertTransfer(*txResult.SettlementTransfer)
+	}
+	return rsp, nil
+}
+
+func validateCreateTransferRequest(req *pb.CreateTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateId(req.GetFromAccountId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("from_account_id", err))
+	}
+	if err := utils.ValidateId(req.GetToAccountId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("to_account_id", err))
+	}
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, helpers.FieldViolation("to_account_id", fmt.Errorf("must be different from from_account_id")))
+	}
+	if err := utils.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, helpers.FieldViolation("amount", err))
+	}
+	if err := utils.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, helpers.FieldViolation("currency", err))
+	}
+	if req.ToCurrency != nil {
+		if err := utils.ValidateCurrency(req.GetToCurrency()); err != nil {
+			violations = append(violations, helpers.FieldViolation("to_currency", err))
+		}
+	}
+	return violations
+}
+
+// validAccount makes sure the account exists, belongs to a customer and holds the requested currency
+func (server *Server) validAccount(ctx context.Context, field string, accountID int64, currency string) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return account, status.Errorf(codes.NotFound, "account [%d] not found", accountID)
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+	if account.IsSystem() {
+		return account, helpers.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			helpers.FieldViolation(field, db.ErrSystemAccount),
+		})
+	}
+	if account.Currency != currency {
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency)
+		return account, helpers.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			helpers.FieldViolation(field, err),
+		})
+	}
+	return account, nil
+}
+
+func (server *Server) GetTransfer(ctx context.Context, req *pb.GetTransferRequest) (*pb.GetTransferResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateGetTransferRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "transfer not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get transfer: %s", err)
+	}
+
+	fromAccount, err := server.store.GetAccount(ctx, transfer.FromAccountID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+	toAccount, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+	if !canView(payload, fromAccount.Owner) && !canView(payload, toAccount.Owner) {
+		return nil, status.Errorf(codes.PermissionDenied, "transfer doesn't belong to the authenticated user")
+	}
+
+	return &pb.GetTransferResponse{
+		Transfer: convertTransfer(transfer),
+	}, nil
+}
+
+func validateGetTransferRequest(req *pb.GetTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateId(req.GetId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("id", err))
+	}
+	return violations
+}
+
+func (server *Server) ListTransfers(ctx context.Context, req *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateListTransfersRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+	if !canView(payload, account.Owner) {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	args := db.ListTransfersParams{
+		FromAccountID: account.ID,
+		ToAccountID:   account.ID,
+		Limit:         req.GetPageSize(),
+		Offset:        (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+	transfers, err := server.store.ListTransfers(ctx, args)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list transfers: %s", err)
+	}
+
+	rsp := &pb.ListTransfersResponse{
+		Transfers: make([]*pb.Transfer, 0, len(transfers)),
+	}
+	for _, transfer := range transfers {
+		rsp.Transfers = append(rsp.Transfers, convertTransfer(transfer))
+	}
+	return rsp, nil
+}
+
+func validateListTransfersRequest(req *pb.ListTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateId(req.GetAccountId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("account_id", err))
+	}
+	if err := utils.ValidatePageId(req.GetPageId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_id", err))
+	}
+	if err := utils.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_size", err))
+	}
+	return violations
+}
+
+func (server *Server) ReverseTransfer(ctx context.Context, req *pb.ReverseTransferRequest) (*pb.ReverseTransferResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateReverseTransferRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.GetTransferId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "transfer not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get transfer: %s", err)
+	}
+
+	// the money goes back out of the recipient's account, so only they or the bank's staff can give it back
+	if !utils.IsStaffRole(payload.Role) {
+		toAccount, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+		}
+		if toAccount.Owner != payload.Username {
+			return nil, status.Errorf(codes.PermissionDenied, "only the recipient of the transfer can reverse it")
+		}
+	}
+
+	txResult, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID:    transfer.ID,
+		Amount:        req.GetAmount(),
+		AfterTransfer: worker.AfterTransfer(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrTransferNotReversible) || errors.Is(err, db.ErrReversalExceedsAmount) || errors.Is(err, db.ErrInsufficientFunds) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reverse transfer: %s", err)
+	}
+
+	return &pb.ReverseTransferResponse{
+		Transfer:         convertTransfer(txResult.Transfer),
+		OriginalTransfer: convertTransfer(txResult.OriginalTransfer),
+		FromAccount:      convertAccount(txResult.FromAccount),
+		FromEntry:        convertEntry(txResult.FromEntry),
+		RefundableAmount: txResult.RefundableAmount,
+	}, nil
+}
+
+func validateReverseTransferRequest(req *pb.ReverseTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateId(req.GetTransferId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("transfer_id", err))
+	}
+	if err := utils.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, helpers.FieldViolation("amount", err))
+	}
+	return violations
+}
@@ -0,0 +1,167 @@
+package grpc_api
+
+import (
+	"context"
+	"testing"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateTransferIdempotency(t *testing.T) {
+	store := db.NewMemoryStore()
+	server := newTestServer(t, store)
+	createTestUser(t, store, "alice", "secret123")
+	createTestUser(t, store, "bob", "secret123")
+	fromAccount := createTestAccount(t, store, "alice", 100)
+	toAccount := createTestAccount(t, store, "bob", 0)
+
+	ctx := authContext("alice", utils.DepositorRole, idempotencyKeyHeader, "transfer-1")
+	req := &pb.CreateTransferRequest{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   toAccount.ID,
+		Amount:        30,
+		Currency:      utils.USD,
+	}
+	first, err := server.CreateTransfer(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a retry with the same key gets the first transfer back instead of moving the money again
+	second, err := server.CreateTransfer(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Transfer.Id != first.Transfer.Id {
+		t.Fatalf("retry made transfer %d, want %d", second.Transfer.Id, first.Transfer.Id)
+	}
+	account, err := store.GetAccount(context.Background(), fromAccount.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Balance != 70 {
+		t.Fatalf("balance is %d after a retried transfer, want 70", account.Balance)
+	}
+
+	req.Amount = 31
+	_, err = server.CreateTransfer(ctx, req)
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("reusing a key for another transfer got %v, want %s", err, codes.AlreadyExists)
+	}
+}
+
+func TestCreateTransferInsufficientFunds(t *testing.T) {
+	store := db.NewMemoryStore()
+	server := newTestServer(t, store)
+	createTestUser(t, store, "alice", "secret123")
+	createTestUser(t, store, "bob", "secret123")
+	fromAccount := createTestAccount(t, store, "alice", 100)
+	toAccount := createTestAccount(t, store, "bob", 0)
+
+	ctx := authContext("alice", utils.DepositorRole)
+	req := &pb.CreateTransferRequest{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   toAccount.ID,
+		Amount:        101,
+		Currency:      utils.USD,
+	}
+	_, err := server.CreateTransfer(ctx, req)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("overdrawing got %v, want %s", err, codes.FailedPrecondition)
+	}
+	for _, account := range []db.Account{fromAccount, toAccount} {
+		got, err := store.GetAccount(context.Background(), account.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Balance != account.Balance {
+			t.Fatalf("balance of account %d is %d after a failed transfer, want %d", account.ID, got.Balance, account.Balance)
+		}
+	}
+
+	// the overdraft limit is part of what can be spent
+	_, err = store.UpdateAccountOverdraftLimit(context.Background(), db.UpdateAccountOverdraftLimitParams{ID: fromAccount.ID, OverdraftLimit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp, err := server.CreateTransfer(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.FromAccount.Balance != -1 {
+		t.Fatalf("balance is %d, want -1", rsp.FromAccount.Balance)
+	}
+}
+
+func TestReverseTransferCaps(t *testing.T) {
+	store := db.NewMemoryStore()
+	server := newTestServer(t, store)
+	createTestUser(t, store, "alice", "secret123")
+	createTestUser(t, store, "bob", "secret123")
+	fromAccount := createTestAccount(t, store, "alice", 100)
+	toAccount := createTestAccount(t, store, "bob", 0)
+
+	transfer, err := server.CreateTransfer(authContext("alice", utils.DepositorRole), &pb.CreateTransferRequest{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   toAccount.ID,
+		Amount:        50,
+		Currency:      utils.USD,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only the recipient gives the money back
+	_, err = server.ReverseTransfer(authContext("alice", utils.DepositorRole), &pb.ReverseTransferRequest{TransferId: transfer.Transfer.Id, Amount: 10})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("sender reversing got %v, want %s", err, codes.PermissionDenied)
+	}
+
+	ctx := authContext("bob", utils.DepositorRole)
+	rsp, err := server.ReverseTransfer(ctx, &pb.ReverseTransferRequest{TransferId: transfer.Transfer.Id, Amount: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.RefundableAmount != 30 {
+		t.Fatalf("refundable amount is %d, want 30", rsp.RefundableAmount)
+	}
+
+	// partial reversals add up to at most the original amount
+	_, err = server.ReverseTransfer(ctx, &pb.ReverseTransferRequest{TransferId: transfer.Transfer.Id, Amount: 31})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("reversing more than is left got %v, want %s", err, codes.FailedPrecondition)
+	}
+	// a reversal can't be reversed itself, not even by the bank's staff
+	_, err = server.ReverseTransfer(authContext("banker", utils.BankerRole), &pb.ReverseTransferRequest{TransferId: rsp.Transfer.Id, Amount: 1})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("reversing a reversal got %v, want %s", err, codes.FailedPrecondition)
+	}
+
+	rsp, err = server.ReverseTransfer(ctx, &pb.ReverseTransferRequest{TransferId: transfer.Transfer.Id, Amount: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.RefundableAmount != 0 {
+		t.Fatalf("refundable amount is %d, want 0", rsp.RefundableAmount)
+	}
+	_, err = server.ReverseTransfer(ctx, &pb.ReverseTransferRequest{TransferId: transfer.Transfer.Id, Amount: 1})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("reversing a fully reversed transfer got %v, want %s", err, codes.FailedPrecondition)
+	}
+
+	for _, want := range []struct {
+		id      int64
+		balance int64
+	}{{fromAccount.ID, 100}, {toAccount.ID, 0}} {
+		account, err := store.GetAccount(context.Background(), want.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if account.Balance != want.balance {
+			t.Fatalf("balance of account %d is %d, want %d", want.id, account.Balance, want.balance)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package grpc_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxLoginChallengeAttempts is how many codes can be tried for a login challenge before it is dropped
+const maxLoginChallengeAttempts = 5
+
+var errInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+func (server *Server) VerifyLoginChallenge(ctx context.Context, req *pb.VerifyLoginChallengeRequest) (*pb.LoginUserResponse, error) {
+	violations := validateVerifyLoginChallengeRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	clientIP := loginClientIP(server.extractMetadata(ctx))
+	if err := server.checkLoginThrottle(ctx, clientIP); err != nil {
+		return nil, err
+	}
+
+	challengeId, _ := uuid.Parse(req.GetChallengeToken())
+	challenge, err := server.store.AddLoginChallengeAttempt(ctx, challengeId)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, helpers.UnauthenticatedError(fmt.Errorf("invalid challenge token"))
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get login challenge: %s", err)
+	}
+	if time.Now().After(challenge.ExpiresAt.Time) || challenge.Attempts > maxLoginChallengeAttempts {
+		_, err = server.store.DeleteLoginChallenge(ctx, challenge.ID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to delete login challenge: %s", err)
+		}
+		return nil, helpers.UnauthenticatedError(fmt.Errorf("login challenge expired, login again"))
+	}
+
+	user, err := server.store.GetUser(ctx, challenge.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+	if err := checkLoginLockout(user); err != nil {
+		return nil, err
+	}
+
+	// a wrong code is a failed login like a wrong password, so that the codes can't be guessed by whoever has the password
+	err = server.verifyTwoFactorCode(ctx, user.Username, req.GetCode())
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			if err := server.recordFailedLogin(ctx, user.Username, clientIP); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	// the challenge is only exchanged once, even when two requests carry a valid code
+	deleted, err := server.store.DeleteLoginChallenge(ctx, challenge.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete login challenge: %s", err)
+	}
+	if deleted == 0 {
+		return nil, helpers.UnauthenticatedError(fmt.Errorf("invalid challenge token"))
+	}
+
+	user, err = server.resetFailedLogins(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return server.createLoginSession(ctx, user)
+}
+
+func validateVerifyLoginChallengeRequest(req *pb.VerifyLoginChallengeRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if _, err := uuid.Parse(req.GetChallengeToken()); err != nil {
+		violations = append(violations, helpers.FieldViolation("challenge_token", fmt.Errorf("is not a valid challenge token")))
+	}
+	if err := utils.ValidateTwoFactorCode(req.GetCode()); err != nil {
+		violations = append(violations, helpers.FieldViolation("code", err))
+	}
+	return violations
+}
+
+func (server *Server) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	twoFactorEnabled, err := server.isTwoFactorEnabled(ctx, payload.Username)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactorEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+
+	secret, uri, err := utils.GenerateTOTPSecret(payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate totp secret: %s", err)
+	}
+	recoveryCodes, err := utils.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate recovery codes: %s", err)
+	}
+	recoveryCodeHashes := make([]string, 0, len(recoveryCodes))
+	for _, recoveryCode := range recoveryCodes {
+		recoveryCodeHashes = append(recoveryCodeHashes, utils.HashRecoveryCode(recoveryCode))
+	}
+
+	_, err = server.store.EnrollTOTPTx(ctx, db.EnrollTOTPTxParams{
+		Username:           payload.Username,
+		Secret:             secret,
+		RecoveryCodeHashes: recoveryCodeHashes,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to enroll totp: %s", err)
+	}
+
+	return &pb.EnrollTOTPResponse{
+		Secret:        secret,
+		OtpauthUri:    uri,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (server *Server) ConfirmTOTP(ctx context.Context, req *pb.ConfirmTOTPRequest) (*pb.ConfirmTOTPResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	if err := utils.ValidateTOTPCode(req.GetCode()); err != nil {
+		return nil, helpers.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{helpers.FieldViolation("code", err)})
+	}
+
+	credential, err := server.store.GetTOTPCredential(ctx, payload.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "totp is not enrolled")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get totp credential: %s", err)
+	}
+	if credential.ConfirmedAt.Valid {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+
+	step, ok := utils.ValidateTOTP(credential.Secret, req.GetCode(), time.Now())
+	if !ok {
+		return nil, helpers.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{helpers.FieldViolation("code", errInvalidTwoFactorCode)})
+	}
+	_, err = server.store.ConfirmTOTPCredential(ctx, db.ConfirmTOTPCredentialParams{
+		Username: payload.Username,
+		Step:     step,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, helpers.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{helpers.FieldViolation("code", errInvalidTwoFactorCode)})
+		}
+		return nil, status.Errorf(codes.Internal, "failed to confirm totp: %s", err)
+	}
+
+	return &pb.ConfirmTOTPResponse{
+		TwoFactorEnabled: true,
+	}, nil
+}
+
+func (server *Server) DisableTOTP(ctx context.Context, req *pb.DisableTOTPRequest) (*pb.DisableTOTPResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateDisableTOTPRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	user, err := server.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+	if !utils.IsPasswordValid(req.GetPassword(), user.Password) {
+		return nil, helpers.UnauthenticatedError(fmt.Errorf("wrong credentials"))
+	}
+
+	credential, err := server.store.GetTOTPCredential(ctx, payload.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is not enabled")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get totp credential: %s", err)
+	}
+	// an enrollment that was never confirmed is dropped without a code
+	if credential.ConfirmedAt.Valid {
+		err = server.verifyTwoFactorCode(ctx, payload.Username, req.GetCode())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = server.store.DisableTOTPTx(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to disable totp: %s", err)
+	}
+
+	return &pb.DisableTOTPResponse{
+		TwoFactorEnabled: false,
+	}, nil
+}
+
+func validateDisableTOTPRequest(req *pb.DisableTOTPRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidatePassword(req.GetPassword()); err != nil {
+		violations = append(violations, helpers.FieldViolation("password", err))
+	}
+	if err := utils.ValidateTwoFactorCode(req.GetCode()); err != nil {
+		violations = append(violations, helpers.FieldViolation("code", err))
+	}
+	return violations
+}
+
+// isTwoFactorEnabled reports whether the user has confirmed a totp secret
+func (server *Server) isTwoFactorEnabled(ctx context.Context, username string) (bool, error) {
+	credential, err := server.store.GetTOTPCredential(ctx, username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, status.Errorf(codes.Internal, "failed to get totp credential: %s", err)
+	}
+	return credential.ConfirmedAt.Valid, nil
+}
+
+// verifyTwoFactorCode checks a totp code or a recovery code of a user with two-factor authentication.
+// Every totp code and recovery code is only accepted once.
+func (server *Server) verifyTwoFactorCode(ctx context.Context, username string, code string) error {
+	if utils.ValidateTOTPCode(code) != nil {
+		_, err := server.store.UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+			Username: username,
+			CodeHash: utils.HashRecoveryCode(code),
+		})
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return helpers.UnauthenticatedError(errInvalidTwoFactorCode)
+			}
+			return status.Errorf(codes.Internal, "failed to use recovery code: %s", err)
+		}
+		return nil
+	}
+
+	credential, err := server.store.GetTOTPCredential(ctx, username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return helpers.UnauthenticatedError(errInvalidTwoFactorCode)
+		}
+		return status.Errorf(codes.Internal, "failed to get totp credential: %s", err)
+	}
+	step, ok := utils.ValidateTOTP(credential.Secret, code, time.Now())
+	if !ok {
+		return helpers.UnauthenticatedError(errInvalidTwoFactorCode)
+	}
+	_, err = server.store.UseTOTPStep(ctx, db.UseTOTPStepParams{
+		Username: username,
+		Step:     step,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return helpers.UnauthenticatedError(errInvalidTwoFactorCode)
+		}
+		return status.Errorf(codes.Internal, "failed to use totp code: %s", err)
+	}
+	return nil
+}
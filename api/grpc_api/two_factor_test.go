@@ -0,0 +1,104 @@
+package grpc_api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/absk07/Go-Bank/worker"
+	"github.com/pquerna/otp/totp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// enableTestTOTP turns on two-factor authentication for username, and returns its totp secret
+func enableTestTOTP(t *testing.T, server *Server, username string) string {
+	ctx := authContext(username, utils.DepositorRole)
+	enrollment, err := server.EnrollTOTP(ctx, &pb.EnrollTOTPRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.ConfirmTOTP(ctx, &pb.ConfirmTOTPRequest{Code: code}); err != nil {
+		t.Fatal(err)
+	}
+	return enrollment.Secret
+}
+
+func TestVerifyLoginChallengeLockout(t *testing.T) {
+	store := db.NewMemoryStore()
+	server := newTestServer(t, store)
+	createTestUser(t, store, "alice", "secret123")
+	secret := enableTestTOTP(t, server, "alice")
+	ctx := context.Background()
+
+	login := func() string {
+		rsp, err := server.LoginUser(ctx, &pb.LoginUserRequest{Username: "alice", Password: "secret123"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !rsp.TwoFactorRequired {
+			t.Fatal("login did not ask for a two-factor code")
+		}
+		return rsp.ChallengeToken
+	}
+
+	// logging in with the password again doesn't start the count of wrong codes over
+	for i := 0; i < maxFailedLogins-1; i++ {
+		_, err := server.VerifyLoginChallenge(ctx, &pb.VerifyLoginChallengeRequest{ChallengeToken: login(), Code: "000000"})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("wrong code got %v, want %s", err, codes.Unauthenticated)
+		}
+	}
+	challengeToken := login()
+	_, err := server.VerifyLoginChallenge(ctx, &pb.VerifyLoginChallengeRequest{ChallengeToken: challengeToken, Code: "ABCDE-FGHJK"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("wrong recovery code got %v, want %s", err, codes.Unauthenticated)
+	}
+
+	// locked out, even with the right code
+	code, err := totp.GenerateCode(secret, time.Now().Add(30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = server.VerifyLoginChallenge(ctx, &pb.VerifyLoginChallengeRequest{ChallengeToken: challengeToken, Code: code})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("right code of a locked out user got %v, want %s", err, codes.PermissionDenied)
+	}
+	_, err = server.LoginUser(ctx, &pb.LoginUserRequest{Username: "alice", Password: "secret123"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("login of a locked out user got %v, want %s", err, codes.PermissionDenied)
+	}
+	if countOutboxTasks(t, store, worker.TaskSendAccountLocked) != 1 {
+		t.Fatal("user was not told about the lockout")
+	}
+
+	// once unlocked, the right code logs in and starts the count over
+	if _, err := server.UnlockUser(authContext("root", utils.AdminRole), &pb.UnlockUserRequest{Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = server.VerifyLoginChallenge(ctx, &pb.VerifyLoginChallengeRequest{ChallengeToken: login(), Code: "000000"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("wrong code got %v, want %s", err, codes.Unauthenticated)
+	}
+	rsp, err := server.VerifyLoginChallenge(ctx, &pb.VerifyLoginChallengeRequest{ChallengeToken: login(), Code: code})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.AccessToken == "" {
+		t.Fatal("no access token after the two-factor code")
+	}
+	user, err := store.GetUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.FailedLoginAttempts != 0 {
+		t.Fatalf("%d failed attempts after logging in, want 0", user.FailedLoginAttempts)
+	}
+}
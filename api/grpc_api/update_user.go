@@ -2,27 +2,32 @@ package grpc_api
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"time"
 
 	db "github.com/absk07/Go-Bank/db/sqlc"
 	"github.com/absk07/Go-Bank/helpers"
 	"github.com/absk07/Go-Bank/pb"
 	"github.com/absk07/Go-Bank/utils"
+	"github.com/absk07/Go-Bank/worker"
+	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/pgtype"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// pendingEmailDuration is how long a changed email is held for the user while it is verified. It outlasts the
+// verify link, so that a link sent late by the worker can still be used.
+const pendingEmailDuration = time.Hour
+
 func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
 	payload, err := server.authorizeUser(ctx)
 	if err != nil {
 		return nil, helpers.UnauthenticatedError(err)
 	}
 
-	if payload != req.Username {
+	if payload.Username != req.Username {
 		return nil, status.Errorf(codes.PermissionDenied, "cannot update other user's info!")
 	}
 
@@ -37,11 +42,73 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 			String: req.GetFullName(),
 			Valid:  req.FullName != nil,
 		},
-		Email: pgtype.Text{
-			String: req.GetEmail(),
-			Valid:  req.Email != nil,
+		Locale: pgtype.Text{
+			String: req.GetLocale(),
+			Valid:  req.Locale != nil,
 		},
 	}
+	var afterUpdate func(q db.Querier, user db.User) error
+	var cancelEmailChange bool
+	if req.Email != nil {
+		user, err := server.store.GetUser(ctx, req.GetUsername())
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, status.Errorf(codes.NotFound, "user not found")
+			}
+			return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+		}
+		// a changed email is kept pending, and only replaces the current one once it is verified
+		if req.GetEmail() != user.Email {
+			// an email in use is turned away early, the unique constraints reject those taken concurrently
+			if _, err := server.store.GetUserByEmail(ctx, req.GetEmail()); err == nil {
+				return nil, status.Errorf(codes.AlreadyExists, "email is already in use")
+			} else if !errors.Is(err, db.ErrRecordNotFound) {
+				return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+			}
+			pendingEmail := pgtype.Text{
+				String: req.GetEmail(),
+				Valid:  true,
+			}
+			// so is an email another user is still waiting to verify, expired ones are released by the update
+			if other, err := server.store.GetUserByPendingEmail(ctx, pendingEmail); err == nil {
+				if other.Username != user.Username {
+					return nil, status.Errorf(codes.AlreadyExists, "email is already in use")
+				}
+			} else if !errors.Is(err, db.ErrRecordNotFound) {
+				return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+			}
+
+			args.PendingEmail = pendingEmail
+			args.PendingEmailExpiresAt = pgtype.Timestamptz{
+				Time:  time.Now().Add(pendingEmailDuration),
+				Valid: true,
+			}
+			afterUpdate = func(q db.Querier, user db.User) error {
+				distributor := worker.NewOutboxTaskDistributor(q)
+				opts := []asynq.Option{
+					asynq.MaxRetry(10),
+					asynq.Queue(worker.QueueCrirical),
+				}
+				taskPayload := &worker.PayloadSendVerifyEmail{
+					Username: user.Username,
+					Email:    user.PendingEmail.String,
+				}
+				if err := distributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...); err != nil {
+					return err
+				}
+				// the current email hears about the change right away, so that its owner can stop it before it is verified
+				noticePayload := &worker.PayloadSendEmailChangeRequested{
+					Username: user.Username,
+					Email:    user.Email,
+					NewEmail: user.PendingEmail.String,
+				}
+				return distributor.DistributeTaskSendEmailChangeRequested(ctx, noticePayload, opts...)
+			}
+		} else {
+			// setting the current email back drops the change still waiting to be verified
+			cancelEmailChange = user.PendingEmail.Valid
+		}
+	}
 	if req.Password != nil {
 		hashedPassword, err := utils.HashPassword(req.GetPassword())
 		if err != nil {
@@ -58,21 +125,24 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 			Valid: true,
 		}
 	}
-	user, err := server.store.UpdateUser(ctx, args)
+	// changing the password logs the user out of every other session
+	result, err := server.store.UpdateUserTx(ctx, db.UpdateUserTxParams{
+		UpdateUserParams:  args,
+		CurrentSessionID:  payload.SessionID,
+		CancelEmailChange: cancelEmailChange,
+		AfterUpdate:       afterUpdate,
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, db.ErrRecordNotFound) {
 			return nil, status.Errorf(codes.NotFound, "user not found")
 		}
+		if db.ErrorCode(err) == db.UniqueViolation {
+			return nil, status.Errorf(codes.AlreadyExists, "email is already in use")
+		}
 		return nil, status.Errorf(codes.Internal, "cannot register user: %s", err)
 	}
 	return &pb.UpdateUserResponse{
-		User: &pb.User{
-			Username:          user.Username,
-			FullName:          user.Fullname,
-			Email:             user.Email,
-			PasswordChangedAt: timestamppb.New(user.PasswordChangedAt.Time),
-			CreatedAt:         timestamppb.New(user.CreatedAt.Time),
-		},
+		User: convertUser(result.User),
 	}, nil
 }
 
@@ -95,5 +165,10 @@ func validateUpdateUserRequest(req *pb.UpdateUserRequest) (violations []*errdeta
 			violations = append(violations, helpers.FieldViolation("email", err))
 		}
 	}
+	if req.Locale != nil {
+		if err := utils.ValidateLocale(req.GetLocale()); err != nil {
+			violations = append(violations, helpers.FieldViolation("locale", err))
+		}
+	}
 	return violations
 }
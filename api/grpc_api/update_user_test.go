@@ -0,0 +1,107 @@
+package grpc_api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdateUserPendingEmailSquatting(t *testing.T) {
+	store := db.NewMemoryStore()
+	server := newTestServer(t, store)
+	createTestUser(t, store, "alice", "secret123")
+	createTestUser(t, store, "bob", "secret123")
+	email := "carol@example.com"
+
+	rsp, err := server.UpdateUser(authContext("alice", utils.DepositorRole), &pb.UpdateUserRequest{Username: "alice", Email: &email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.User.PendingEmail != email {
+		t.Fatalf("pending email is %q, want %q", rsp.User.PendingEmail, email)
+	}
+
+	// the email is held for alice while she can still verify it
+	_, err = server.UpdateUser(authContext("bob", utils.DepositorRole), &pb.UpdateUserRequest{Username: "bob", Email: &email})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("claiming a pending email got %v, want %s", err, codes.AlreadyExists)
+	}
+
+	// and released once it expires unverified
+	_, err = store.UpdateUser(context.Background(), db.UpdateUserParams{
+		Username:              "alice",
+		PendingEmailExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp, err = server.UpdateUser(authContext("bob", utils.DepositorRole), &pb.UpdateUserRequest{Username: "bob", Email: &email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.User.PendingEmail != email {
+		t.Fatalf("pending email is %q, want %q", rsp.User.PendingEmail, email)
+	}
+
+	alice, err := store.GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.PendingEmail.Valid {
+		t.Fatalf("expired pending email %q was kept", alice.PendingEmail.String)
+	}
+}
+
+func TestUpdateUserEmailSetBack(t *testing.T) {
+	store := db.NewMemoryStore()
+	server := newTestServer(t, store)
+	user := createTestUser(t, store, "alice", "secret123")
+	ctx := authContext("alice", utils.DepositorRole)
+	email := "carol@example.com"
+
+	_, err := server.UpdateUser(ctx, &pb.UpdateUserRequest{Username: "alice", Email: &email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyEmail, err := store.CreateVerifyEmail(context.Background(), db.CreateVerifyEmailParams{
+		Username:   "alice",
+		Email:      email,
+		SecretCode: uuid.New().String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// setting the current email back cancels the change
+	rsp, err := server.UpdateUser(ctx, &pb.UpdateUserRequest{Username: "alice", Email: &user.Email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.User.PendingEmail != "" {
+		t.Fatalf("pending email %q was kept", rsp.User.PendingEmail)
+	}
+
+	// and uses up the link sent to verify it
+	_, err = server.VerifyEmail(context.Background(), &pb.VerifyEmailRequest{
+		EmailId:    verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCode,
+	})
+	if err == nil {
+		t.Fatal("the link of a cancelled email change verified it")
+	}
+	alice, err := store.GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.Email != user.Email {
+		t.Fatalf("email is %q, want %q", alice.Email, user.Email)
+	}
+}
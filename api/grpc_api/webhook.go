@@ -0,0 +1,183 @@
+package grpc_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/pb"
+	"github.com/absk07/Go-Bank/utils"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateWebhookEndpoint(ctx context.Context, req *pb.CreateWebhookEndpointRequest) (*pb.CreateWebhookEndpointResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateCreateWebhookEndpointRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	secret, err := utils.GenerateWebhookSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate webhook secret: %s", err)
+	}
+
+	eventTypes := slices.Clone(req.GetEventTypes())
+	slices.Sort(eventTypes)
+	endpoint, err := server.store.CreateWebhookEndpoint(ctx, db.CreateWebhookEndpointParams{
+		Owner:      payload.Username,
+		Url:        req.GetUrl(),
+		Secret:     secret,
+		EventTypes: slices.Compact(eventTypes),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create webhook endpoint: %s", err)
+	}
+
+	// the secret is never returned again, the owner has to keep it to verify signatures
+	return &pb.CreateWebhookEndpointResponse{
+		Endpoint: convertWebhookEndpoint(endpoint),
+		Secret:   endpoint.Secret,
+	}, nil
+}
+
+func validateCreateWebhookEndpointRequest(req *pb.CreateWebhookEndpointRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateWebhookURL(req.GetUrl()); err != nil {
+		violations = append(violations, helpers.FieldViolation("url", err))
+	}
+	if len(req.GetEventTypes()) == 0 {
+		violations = append(violations, helpers.FieldViolation("event_types", fmt.Errorf("must contain at least one event")))
+	}
+	for _, eventType := range req.GetEventTypes() {
+		if err := utils.ValidateWebhookEvent(eventType); err != nil {
+			violations = append(violations, helpers.FieldViolation("event_types", err))
+			break
+		}
+	}
+	return violations
+}
+
+func (server *Server) ListWebhookEndpoints(ctx context.Context, req *pb.ListWebhookEndpointsRequest) (*pb.ListWebhookEndpointsResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	endpoints, err := server.store.ListWebhookEndpoints(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list webhook endpoints: %s", err)
+	}
+
+	rsp := &pb.ListWebhookEndpointsResponse{
+		Endpoints: make([]*pb.WebhookEndpoint, 0, len(endpoints)),
+	}
+	for _, endpoint := range endpoints {
+		rsp.Endpoints = append(rsp.Endpoints, convertWebhookEndpoint(endpoint))
+	}
+	return rsp, nil
+}
+
+func (server *Server) DeleteWebhookEndpoint(ctx context.Context, req *pb.DeleteWebhookEndpointRequest) (*pb.DeleteWebhookEndpointResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateWebhookEndpointId("id", req.GetId())
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	endpoint, err := server.getWebhookEndpoint(ctx, req.GetId(), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// deliveries of the endpoint are removed along with it, pending tasks are dropped by the worker
+	err = server.store.DeleteWebhookEndpoint(ctx, endpoint.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete webhook endpoint: %s", err)
+	}
+
+	return &pb.DeleteWebhookEndpointResponse{
+		Endpoint: convertWebhookEndpoint(endpoint),
+	}, nil
+}
+
+func (server *Server) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, helpers.UnauthenticatedError(err)
+	}
+
+	violations := validateListWebhookDeliveriesRequest(req)
+	if violations != nil {
+		return nil, helpers.InvalidArgumentError(violations)
+	}
+
+	endpoint, err := server.getWebhookEndpoint(ctx, req.GetEndpointId(), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	args := db.ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		Limit:      req.GetPageSize(),
+		Offset:     (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, args)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list webhook deliveries: %s", err)
+	}
+
+	rsp := &pb.ListWebhookDeliveriesResponse{
+		Deliveries: make([]*pb.WebhookDelivery, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		rsp.Deliveries = append(rsp.Deliveries, convertWebhookDelivery(delivery))
+	}
+	return rsp, nil
+}
+
+func validateListWebhookDeliveriesRequest(req *pb.ListWebhookDeliveriesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = validateWebhookEndpointId("endpoint_id", req.GetEndpointId())
+	if err := utils.ValidatePageId(req.GetPageId()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_id", err))
+	}
+	if err := utils.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, helpers.FieldViolation("page_size", err))
+	}
+	return violations
+}
+
+func validateWebhookEndpointId(field string, id int64) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := utils.ValidateId(id); err != nil {
+		violations = append(violations, helpers.FieldViolation(field, err))
+	}
+	return violations
+}
+
+// getWebhookEndpoint returns a webhook endpoint if it belongs to the authenticated user,
+// endpoints hold a signing secret so not even bankers can view other users' ones
+func (server *Server) getWebhookEndpoint(ctx context.Context, id int64, payload *utils.Payload) (db.WebhookEndpoint, error) {
+	endpoint, err := server.store.GetWebhookEndpoint(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return endpoint, status.Errorf(codes.NotFound, "webhook endpoint not found")
+		}
+		return endpoint, status.Errorf(codes.Internal, "failed to get webhook endpoint: %s", err)
+	}
+	if endpoint.Owner != payload.Username {
+		return endpoint, status.Errorf(codes.PermissionDenied, "webhook endpoint doesn't belong to the authenticated user")
+	}
+	return endpoint, nil
+}
@@ -1,40 +1,51 @@
 package rest_api
 
 import (
+	"fmt"
+
 	db "github.com/absk07/Go-Bank/db/sqlc"
 	"github.com/absk07/Go-Bank/middlewares"
 	"github.com/absk07/Go-Bank/utils"
-	"github.com/absk07/Go-Bank/worker"
 	"github.com/gin-gonic/gin"
 )
 
 type Server struct {
 	config utils.Config
-	store *db.Store
+	store db.Store
 	router *gin.Engine
-	taskDistributor worker.TaskDistributor
+	tokenMaker utils.TokenMaker
 }
 
-func NewServer(config utils.Config, store *db.Store, taskDistributor worker.TaskDistributor) *Server {
+func NewServer(config utils.Config, store db.Store) (*Server, error) {
+	keyCipher, err := utils.NewSigningKeyCipher(config.SigningKeyEncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create signing key cipher: %w", err)
+	}
+	// the same keys as the grpc server, so that tokens signed after a rotation are accepted
+	tokenMaker, err := db.NewSigningKeyring(config, store, keyCipher)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
 	server := &Server{
 		config: config,
 		store: store,
-		taskDistributor: taskDistributor,
+		tokenMaker: tokenMaker,
 	}
 	router := gin.Default()
+	isAuthenticated := middlewares.IsAuthenticated(tokenMaker)
 
 	// routes
 	router.POST("/v1/register", server.register)
 	router.POST("/v1/login", server.login)
 	router.GET("/v1/verify_email", server.VerifyEmail)
 	router.POST("/v1/tokens/renew_access", server.renewAccessToken)
-	router.POST("/v1/account/add", middlewares.IsAuthenticated, server.createAccount)
-	router.GET("/v1/accounts", middlewares.IsAuthenticated, server.getAccounts)
-	router.GET("/v1/account/:id", middlewares.IsAuthenticated, server.getAccountById)
-	router.POST("/v1/transfer/add", middlewares.IsAuthenticated, server.createTransfer)
+	router.POST("/v1/account/add", isAuthenticated, server.createAccount)
+	router.GET("/v1/accounts", isAuthenticated, server.getAccounts)
+	router.GET("/v1/account/:id", isAuthenticated, server.getAccountById)
+	router.POST("/v1/transfer/add", isAuthenticated, server.createTransfer)
 
 	server.router = router
-	return server
+	return server, nil
 }
 
 func (server *Server) Start(addr string) error {
@@ -20,12 +20,19 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse(err))
 		return
 	}
-	refresh_token_id, username, err := utils.VerifyToken(req.RefreshToken, server.config.Secret)
+	payload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse(err))
 		return
 	}
-	session, err := server.store.GetSession(ctx, refresh_token_id)
+	if payload.Type != utils.TokenTypeRefresh {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Invalid refresh token!",
+		})
+		return
+	}
+	session, err := server.store.GetSession(ctx, payload.SessionID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, helpers.ErrorResponse(err))
@@ -39,29 +46,41 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 			"success": false,
 			"message": "Session Blocked!",
 		})
+		return
 	}
-	if session.Username != username {
+	if session.Username != payload.Username {
 		ctx.JSON(http.StatusUnauthorized, gin.H{
 			"success": false,
 			"message": "Incorrect session user!",
 		})
+		return
 	}
 	if session.RefreshToken != req.RefreshToken {
 		ctx.JSON(http.StatusUnauthorized, gin.H{
 			"success": false,
 			"message": "Refresh token mismatch!",
 		})
+		return
 	}
 	if time.Now().After(session.ExpiresAt.Time) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{
 			"success": false,
 			"message": "Expired session!",
 		})
+		return
+	}
+	// the role is read again, so that a role change applies from the next access token
+	user, err := server.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse(err))
+		return
 	}
-	_, token, token_expiration, err := utils.GenerateToken(
-		username,
+	token, tokenPayload, err := server.tokenMaker.CreateToken(
+		user.Username,
+		user.Role,
+		session.ID,
+		utils.TokenTypeAccess,
 		server.config.TokenDuration,
-		server.config.Secret,
 	)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse(err))
@@ -71,7 +90,7 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		"success": true,
 		"data": gin.H{
 			"token":            token,
-			"token_expires_at": token_expiration.Time,
+			"token_expires_at": tokenPayload.ExpiredAt,
 		},
 	})
 }
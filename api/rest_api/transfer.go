@@ -2,11 +2,13 @@ package rest_api
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 
 	db "github.com/absk07/Go-Bank/db/sqlc"
 	"github.com/absk07/Go-Bank/helpers"
+	"github.com/absk07/Go-Bank/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -40,12 +42,28 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 	args := db.TransferTxParams{
-		FromAccountId: req.FromAccountId,
-		ToAccountID:   req.ToAccountId,
-		Amount:        req.Amount,
+		FromAccountId:  req.FromAccountId,
+		ToAccountID:    req.ToAccountId,
+		Amount:         req.Amount,
+		Username:       current_user,
+		IdempotencyKey: ctx.GetHeader("Idempotency-Key"),
+	}
+	if args.IdempotencyKey != "" {
+		if err := utils.ValidateIdempotencyKey(args.IdempotencyKey); err != nil {
+			ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse(fmt.Errorf("Idempotency-Key %w", err)))
+			return
+		}
 	}
 	res, err := server.store.TransferTx(ctx, args)
 	if err != nil {
+		if errors.Is(err, db.ErrIdempotencyKeyReused) {
+			ctx.JSON(http.StatusConflict, helpers.ErrorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusUnprocessableEntity, helpers.ErrorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse(err))
 		return
 	}
@@ -65,6 +83,10 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency s
 		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse(err))
 		return account, false
 	}
+	if account.IsSystem() {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse(db.ErrSystemAccount))
+		return account, false
+	}
 	if account.Currency != currency {
 		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency)
 		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse(err))
@@ -2,6 +2,7 @@ package rest_api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
@@ -41,17 +42,17 @@ func (server *Server) register(ctx *gin.Context) {
 			Password: hashedPassword,
 			Fullname: req.FullName,
 			Email:    req.Email,
+			Locale:   utils.DefaultLocale,
 		},
-		AfterCreate: func(user db.User) error {
+		AfterCreate: func(q db.Querier, user db.User) error {
 			taskPayload := &worker.PayloadSendVerifyEmail{
 				Username: user.Username,
 			}
 			opts := []asynq.Option{
 				asynq.MaxRetry(10),
-				asynq.ProcessIn(10 * time.Second),
 				asynq.Queue(worker.QueueCrirical),
 			}
-			return server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
+			return worker.NewOutboxTaskDistributor(q).DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
 		},
 	}
 
@@ -92,6 +93,13 @@ func (server *Server) login(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse(err))
 		return
 	}
+	if user.LockedUntil.Valid && time.Now().Before(user.LockedUntil.Time) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "account is locked after too many failed logins",
+		})
+		return
+	}
 	IsPasswordValid := utils.IsPasswordValid(req.Password, user.Password)
 	if !IsPasswordValid {
 		ctx.JSON(http.StatusUnauthorized, gin.H{
@@ -100,30 +108,48 @@ func (server *Server) login(ctx *gin.Context) {
 		})
 		return
 	}
-	var token, refreshToken string
-	var refreshTokenId uuid.UUID
-	var token_expiration, refreshToken_expiration pgtype.Timestamptz
-	_, token, token_expiration, err = utils.GenerateToken(
+	// this API has no second login step, so users with two-factor authentication log in through the gateway
+	credential, err := server.store.GetTOTPCredential(ctx, user.Username)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse(err))
+		return
+	}
+	if err == nil && credential.ConfirmedAt.Valid {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "two-factor authentication required",
+		})
+		return
+	}
+	sessionId := uuid.New()
+	token, tokenPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
+		user.Role,
+		sessionId,
+		utils.TokenTypeAccess,
 		server.config.TokenDuration,
-		server.config.Secret,
 	)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse(err))
 		return
 	}
-	refreshTokenId, refreshToken, refreshToken_expiration, err = utils.GenerateToken(
+	refreshToken, refreshTokenPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
+		user.Role,
+		sessionId,
+		utils.TokenTypeRefresh,
 		server.config.RefereshTokenDuration,
-		server.config.Secret,
 	)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse(err))
 		return
 	}
-	// fmt.Printf("refreshToken_expiration: %+v\n", refreshToken_expiration)
+	refreshToken_expiration := pgtype.Timestamptz{
+		Time:  refreshTokenPayload.ExpiredAt,
+		Valid: true,
+	}
 	SessionParams := db.CreateSessionParams{
-		ID:           refreshTokenId,
+		ID:           sessionId,
 		Username:     user.Username,
 		RefreshToken: refreshToken,
 		UserAgent:    ctx.Request.UserAgent(),
@@ -147,7 +173,7 @@ func (server *Server) login(ctx *gin.Context) {
 			"password_changed_at":      user.PasswordChangedAt,
 			"created_at":               user.CreatedAt,
 			"token":                    token,
-			"token_expires_at":         token_expiration.Time,
+			"token_expires_at":         tokenPayload.ExpiredAt,
 			"refresh_token":            refreshToken,
 			"refresh_token_expires_at": refreshToken_expiration.Time,
 		},
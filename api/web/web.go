@@ -0,0 +1,29 @@
+// Package web serves the pages that the links of the emails open
+package web
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+const ResetPasswordPath = "/reset_password"
+
+//go:embed reset_password.html
+var resetPasswordPage []byte
+
+// ResetPasswordPage serves the page a password reset email links to. The page reads the reset
+// from the fragment of its URL, and sends it with the new password to the ResetPassword API.
+func ResetPasswordPage() http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			res.Header().Set("Allow", "GET, HEAD")
+			http.Error(res, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		res.Header().Set("Content-Type", "text/html; charset=utf-8")
+		res.Header().Set("Cache-Control", "no-store")
+		res.Header().Set("Referrer-Policy", "no-referrer")
+		res.Header().Set("X-Frame-Options", "DENY")
+		res.Write(resetPasswordPage)
+	})
+}
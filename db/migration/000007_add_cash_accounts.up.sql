@@ -0,0 +1,16 @@
+ALTER TABLE "accounts" ADD COLUMN "kind" varchar NOT NULL DEFAULT 'customer';
+
+ALTER TABLE "accounts" DROP CONSTRAINT "owner_currency_key";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_kind_key" UNIQUE ("owner", "currency", "kind");
+
+-- the bank owns the system accounts; its empty password hash can never match, so it cannot log in
+INSERT INTO "users" ("username", "password", "fullname", "email", "is_email_verified")
+VALUES ('gobank', '', 'Go Bank', 'system@gobank.internal', true);
+
+-- one cash account per currency is the counterparty of every deposit and withdrawal
+INSERT INTO "accounts" ("owner", "balance", "currency", "kind")
+VALUES
+  ('gobank', 0, 'USD', 'cash'),
+  ('gobank', 0, 'EUR', 'cash'),
+  ('gobank', 0, 'INR', 'cash');
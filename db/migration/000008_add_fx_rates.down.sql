@@ -0,0 +1,16 @@
+DELETE FROM "entries" WHERE "account_id" IN (SELECT "id" FROM "accounts" WHERE "kind" = 'fx');
+
+UPDATE "transfers" SET "source_transfer_id" = NULL;
+
+DELETE FROM "transfers" WHERE "from_account_id" IN (SELECT "id" FROM "accounts" WHERE "kind" = 'fx')
+  OR "to_account_id" IN (SELECT "id" FROM "accounts" WHERE "kind" = 'fx');
+
+DELETE FROM "accounts" WHERE "kind" = 'fx';
+
+ALTER TABLE "transfers" DROP COLUMN "source_transfer_id";
+
+ALTER TABLE "transfers" DROP COLUMN "fx_rate_id";
+
+ALTER TABLE "transfers" DROP COLUMN "exchange_rate";
+
+DROP TABLE IF EXISTS "fx_rates";
@@ -0,0 +1,30 @@
+CREATE TABLE "fx_rates" (
+  "id" bigserial PRIMARY KEY,
+  "from_currency" varchar NOT NULL,
+  "to_currency" varchar NOT NULL,
+  "rate" numeric(20,10) NOT NULL,
+  "effective_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "fx_rates" ("from_currency", "to_currency", "effective_at");
+
+ALTER TABLE "fx_rates" ADD CONSTRAINT "rate_positive" CHECK ("rate" > 0);
+
+-- a cross-currency transfer is booked as two legs through the fx position accounts:
+-- customer -> fx position (from currency) and fx position (to currency) -> customer
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate" numeric(20,10);
+
+ALTER TABLE "transfers" ADD COLUMN "fx_rate_id" bigint;
+
+ALTER TABLE "transfers" ADD COLUMN "source_transfer_id" bigint;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("fx_rate_id") REFERENCES "fx_rates" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("source_transfer_id") REFERENCES "transfers" ("id");
+
+INSERT INTO "accounts" ("owner", "balance", "currency", "kind")
+VALUES
+  ('gobank', 0, 'USD', 'fx'),
+  ('gobank', 0, 'EUR', 'fx'),
+  ('gobank', 0, 'INR', 'fx');
@@ -0,0 +1 @@
+ALTER TABLE "transfers" DROP COLUMN "reversed_transfer_id";
@@ -0,0 +1,4 @@
+-- depositors only see their own data, bankers can view any customer and admins can change accounts
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'depositor';
+
+ALTER TABLE "users" ADD CONSTRAINT "role_valid" CHECK ("role" IN ('depositor', 'banker', 'admin'));
@@ -0,0 +1,11 @@
+-- the keys that sign access and refresh tokens. The newest key without a retirement
+-- time signs new tokens, the others still verify tokens until they retire.
+CREATE TABLE "signing_keys" (
+  "kid" varchar PRIMARY KEY,
+  "algorithm" varchar NOT NULL,
+  "key" bytea NOT NULL,
+  "retires_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "signing_keys" ("algorithm", "created_at");
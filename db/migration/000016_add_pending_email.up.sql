@@ -0,0 +1,2 @@
+-- a changed email waits here until it is verified, the current one stays in use meanwhile
+ALTER TABLE "users" ADD COLUMN "pending_email" varchar;
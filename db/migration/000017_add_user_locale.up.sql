@@ -0,0 +1,2 @@
+-- the language of the emails sent to the user
+ALTER TABLE "users" ADD COLUMN "locale" varchar NOT NULL DEFAULT 'en';
@@ -0,0 +1,10 @@
+-- users get every notification unless they opt out of it on a channel, so only the opt-outs need a row
+CREATE TABLE "notification_preferences" (
+  "username" varchar NOT NULL,
+  "channel" varchar NOT NULL,
+  "transfer_notifications" bool NOT NULL DEFAULT true,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "channel")
+);
+
+ALTER TABLE "notification_preferences" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
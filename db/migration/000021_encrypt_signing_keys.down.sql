@@ -0,0 +1,5 @@
+-- encrypted keys can't be told apart from plain ones without the column, so they are dropped
+-- and the key from the config is added back by the next server that starts
+DELETE FROM "signing_keys" WHERE "encrypted";
+
+ALTER TABLE "signing_keys" DROP COLUMN "encrypted";
@@ -0,0 +1,3 @@
+-- signing keys are encrypted with a key from the config. The keys stored before are
+-- encrypted in place by the first server that loads them.
+ALTER TABLE "signing_keys" ADD COLUMN "encrypted" boolean NOT NULL DEFAULT false;
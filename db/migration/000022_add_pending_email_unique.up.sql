@@ -0,0 +1,3 @@
+-- two users can't wait for the same email, and verifying a pending email fails on
+-- users_email_key when another user has taken it since
+CREATE UNIQUE INDEX "users_pending_email_key" ON "users" ("pending_email");
@@ -0,0 +1,3 @@
+-- the responses of webhook endpoints are not kept, only their status code. A url reaching
+-- an internal service would otherwise let its owner read the service's responses.
+ALTER TABLE "webhook_deliveries" DROP COLUMN "response_body";
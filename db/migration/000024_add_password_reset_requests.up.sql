@@ -0,0 +1,12 @@
+-- every request for a password reset, kept to throttle them per client ip and per email
+CREATE TABLE "password_reset_requests" (
+  "id" bigserial PRIMARY KEY,
+  "email" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "link_sent" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "password_reset_requests" ("client_ip", "created_at");
+
+CREATE INDEX ON "password_reset_requests" ("email", "created_at");
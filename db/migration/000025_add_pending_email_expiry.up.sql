@@ -0,0 +1,4 @@
+-- a pending email is only held until it expires, so that an email that is never verified can be claimed again
+ALTER TABLE "users" ADD COLUMN "pending_email_expires_at" timestamptz;
+
+UPDATE "users" SET "pending_email_expires_at" = now() + interval '1 hour' WHERE "pending_email" IS NOT NULL;
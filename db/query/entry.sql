@@ -15,4 +15,18 @@ SELECT * FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
-OFFSET $3;
\ No newline at end of file
+OFFSET $3;
+
+-- name: ListEntriesBetween :many
+SELECT * FROM entries
+WHERE
+    account_id = sqlc.arg(account_id)
+    AND created_at >= sqlc.arg(start_time)
+    AND created_at < sqlc.arg(end_time)
+ORDER BY created_at, id;
+
+-- name: SumEntriesSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE
+    account_id = sqlc.arg(account_id)
+    AND created_at >= sqlc.arg(since);
\ No newline at end of file
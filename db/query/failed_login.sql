@@ -0,0 +1,42 @@
+-- name: CreateFailedLogin :one
+INSERT INTO failed_logins (
+    username,
+    client_ip
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: CountFailedLoginsByClientIp :one
+SELECT count(*) FROM failed_logins
+WHERE
+    client_ip = @client_ip
+    AND created_at > @since;
+
+-- name: DeleteFailedLoginsBefore :execrows
+DELETE FROM failed_logins
+WHERE created_at < @before;
+
+-- name: AddUserFailedLoginAttempt :one
+UPDATE users
+SET
+    failed_login_attempts = failed_login_attempts + 1
+WHERE
+    username = @username
+RETURNING *;
+
+-- name: LockUser :one
+UPDATE users
+SET
+    locked_until = @locked_until
+WHERE
+    username = @username
+RETURNING *;
+
+-- name: UnlockUser :one
+UPDATE users
+SET
+    failed_login_attempts = 0,
+    locked_until = NULL
+WHERE
+    username = @username
+RETURNING *;
@@ -0,0 +1,27 @@
+-- name: CreateFxRate :one
+INSERT INTO fx_rates (
+  from_currency,
+  to_currency,
+  rate,
+  effective_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetFxRate :one
+SELECT * FROM fx_rates
+WHERE
+    from_currency = sqlc.arg(from_currency)
+    AND to_currency = sqlc.arg(to_currency)
+    AND effective_at <= sqlc.arg(at)
+ORDER BY effective_at DESC, id DESC
+LIMIT 1;
+
+-- name: ListFxRates :many
+SELECT * FROM fx_rates
+WHERE
+    from_currency = $1
+    AND to_currency = $2
+ORDER BY effective_at DESC, id DESC
+LIMIT $3
+OFFSET $4;
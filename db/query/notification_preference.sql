@@ -0,0 +1,22 @@
+-- name: UpsertNotificationPreference :one
+INSERT INTO notification_preferences (
+  username,
+  channel,
+  transfer_notifications
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (username, channel) DO UPDATE
+SET
+  transfer_notifications = EXCLUDED.transfer_notifications,
+  updated_at = now()
+RETURNING *;
+
+-- name: GetNotificationPreference :one
+SELECT * FROM notification_preferences
+WHERE username = $1 AND channel = $2 LIMIT 1;
+
+-- name: ListNotificationPreferences :many
+SELECT * FROM notification_preferences
+WHERE username = $1
+ORDER BY channel;
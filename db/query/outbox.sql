@@ -0,0 +1,25 @@
+-- name: CreateOutboxMessage :one
+INSERT INTO outbox (
+    task_type,
+    payload,
+    queue,
+    max_retry
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListUnsentOutboxMessages :many
+SELECT * FROM outbox
+WHERE sent_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxMessageSent :one
+UPDATE outbox
+SET
+    sent_at = now()
+WHERE
+    id = @id
+    AND sent_at IS NULL
+RETURNING *;
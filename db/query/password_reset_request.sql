@@ -0,0 +1,25 @@
+-- name: CreatePasswordResetRequest :one
+INSERT INTO password_reset_requests (
+    email,
+    client_ip,
+    link_sent
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: CountPasswordResetRequestsByClientIp :one
+SELECT count(*) FROM password_reset_requests
+WHERE
+    client_ip = @client_ip
+    AND created_at > @since;
+
+-- name: CountSentPasswordResetRequests :one
+SELECT count(*) FROM password_reset_requests
+WHERE
+    email = @email
+    AND link_sent = TRUE
+    AND created_at > @since;
+
+-- name: DeletePasswordResetRequestsBefore :execrows
+DELETE FROM password_reset_requests
+WHERE created_at < @before;
@@ -14,4 +14,36 @@ RETURNING *;
 
 -- name: GetSession :one
 SELECT * FROM sessions
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+WHERE id = $1 LIMIT 1;
+
+-- name: ListSessions :many
+SELECT * FROM sessions
+WHERE username = $1
+ORDER BY created_at DESC, id
+LIMIT $2
+OFFSET $3;
+
+-- name: BlockSession :one
+UPDATE sessions
+SET is_blocked = true
+WHERE id = $1
+RETURNING *;
+
+-- name: BlockUserSessions :execrows
+UPDATE sessions
+SET is_blocked = true
+WHERE
+  username = sqlc.arg(username)
+  AND id <> sqlc.arg(except_id)
+  AND is_blocked = false;
+
+-- name: RotateSessionRefreshToken :one
+UPDATE sessions
+SET
+  refresh_token = sqlc.arg(new_refresh_token),
+  expires_at = sqlc.arg(expires_at)
+WHERE
+  id = sqlc.arg(id)
+  AND refresh_token = sqlc.arg(refresh_token)
+  AND is_blocked = false
+RETURNING *;
@@ -0,0 +1,36 @@
+-- name: CreateSigningKey :one
+INSERT INTO signing_keys (
+  kid,
+  algorithm,
+  key,
+  encrypted
+) VALUES (
+  $1, $2, $3, true
+)
+RETURNING *;
+
+-- name: ListSigningKeys :many
+SELECT * FROM signing_keys
+WHERE
+  algorithm = sqlc.arg(algorithm)
+  AND (retires_at IS NULL OR retires_at > sqlc.arg(now))
+ORDER BY created_at DESC, kid;
+
+-- name: RetireSigningKeys :many
+UPDATE signing_keys
+SET retires_at = sqlc.arg(retires_at)
+WHERE
+  algorithm = sqlc.arg(algorithm)
+  AND kid <> sqlc.arg(except_kid)
+  AND (retires_at IS NULL OR retires_at > sqlc.arg(retires_at))
+RETURNING *;
+
+-- name: EncryptSigningKey :one
+UPDATE signing_keys
+SET
+  key = sqlc.arg(key),
+  encrypted = true
+WHERE
+  kid = sqlc.arg(kid)
+  AND NOT encrypted
+RETURNING *;
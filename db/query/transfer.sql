@@ -7,6 +7,18 @@ INSERT INTO transfers (
   $1, $2, $3
 ) RETURNING *;
 
+-- name: CreateFxTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  exchange_rate,
+  fx_rate_id,
+  source_transfer_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
 -- name: GetTransfer :one
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
@@ -18,4 +30,23 @@ WHERE
     to_account_id = $2
 ORDER BY id
 LIMIT $3
-OFFSET $4;
\ No newline at end of file
+OFFSET $4;
+
+-- name: CreateReversalTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  reversed_transfer_id
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: SumReversedAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM transfers
+WHERE reversed_transfer_id = $1;
\ No newline at end of file
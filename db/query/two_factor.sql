@@ -0,0 +1,83 @@
+-- name: UpsertTOTPCredential :one
+INSERT INTO totp_credentials (
+  username,
+  secret
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (username) DO UPDATE
+SET
+  secret = EXCLUDED.secret,
+  confirmed_at = NULL,
+  last_used_step = 0,
+  created_at = now()
+RETURNING *;
+
+-- name: GetTOTPCredential :one
+SELECT * FROM totp_credentials
+WHERE username = $1 LIMIT 1;
+
+-- name: ConfirmTOTPCredential :one
+UPDATE totp_credentials
+SET
+  confirmed_at = now(),
+  last_used_step = sqlc.arg(step)
+WHERE
+  username = sqlc.arg(username)
+  AND confirmed_at IS NULL
+  AND last_used_step < sqlc.arg(step)
+RETURNING *;
+
+-- name: UseTOTPStep :one
+UPDATE totp_credentials
+SET last_used_step = sqlc.arg(step)
+WHERE
+  username = sqlc.arg(username)
+  AND last_used_step < sqlc.arg(step)
+RETURNING *;
+
+-- name: DeleteTOTPCredential :exec
+DELETE FROM totp_credentials
+WHERE username = $1;
+
+-- name: CreateRecoveryCode :one
+INSERT INTO recovery_codes (
+  username,
+  code_hash
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: UseRecoveryCode :one
+UPDATE recovery_codes
+SET used_at = now()
+WHERE
+  username = $1
+  AND code_hash = $2
+  AND used_at IS NULL
+RETURNING *;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE username = $1;
+
+-- name: CreateLoginChallenge :one
+INSERT INTO login_challenges (
+  id,
+  username,
+  expires_at
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: AddLoginChallengeAttempt :one
+UPDATE login_challenges
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteLoginChallenge :execrows
+DELETE FROM login_challenges
+WHERE id = $1;
@@ -3,9 +3,10 @@ INSERT INTO users (
   username,
   password,
   fullname,
-  email
+  email,
+  locale
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5
 )
 RETURNING *;
 
@@ -13,6 +14,14 @@ RETURNING *;
 SELECT * FROM users
 WHERE username = $1 LIMIT 1;
 
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1 LIMIT 1;
+
+-- name: GetUserByPendingEmail :one
+SELECT * FROM users
+WHERE pending_email = $1 AND pending_email_expires_at > now() LIMIT 1;
+
 -- name: UpdateUser :one
 UPDATE users
 SET
@@ -20,7 +29,41 @@ SET
   password_changed_at = COALESCE(sqlc.narg(password_changed_at), password_changed_at),
   fullname = COALESCE(sqlc.narg(fullname), fullname),
   email = COALESCE(sqlc.narg(email), email),
-  is_email_verified = COALESCE(sqlc.narg(is_email_verified), is_email_verified)
+  pending_email = COALESCE(sqlc.narg(pending_email), pending_email),
+  pending_email_expires_at = COALESCE(sqlc.narg(pending_email_expires_at), pending_email_expires_at),
+  is_email_verified = COALESCE(sqlc.narg(is_email_verified), is_email_verified),
+  role = COALESCE(sqlc.narg(role), role),
+  locale = COALESCE(sqlc.narg(locale), locale)
 WHERE
   username = sqlc.arg(username)
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: CancelUserEmailChange :one
+UPDATE users
+SET
+  pending_email = NULL,
+  pending_email_expires_at = NULL
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: ReleaseExpiredPendingEmail :exec
+UPDATE users
+SET
+  pending_email = NULL,
+  pending_email_expires_at = NULL
+WHERE
+  pending_email = sqlc.arg(pending_email)
+  AND pending_email_expires_at <= now();
+
+-- name: ConfirmUserEmail :one
+UPDATE users
+SET
+  email = pending_email,
+  pending_email = NULL,
+  pending_email_expires_at = NULL,
+  is_email_verified = true
+WHERE
+  username = sqlc.arg(username)
+  AND pending_email = sqlc.arg(pending_email)
+  AND pending_email_expires_at > now()
+RETURNING *;
@@ -16,4 +16,13 @@ WHERE
     AND secret_code = @secret_code
     AND is_used = FALSE
     AND expired_at > now()
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: InvalidateVerifyEmails :exec
+UPDATE verify_emails
+SET
+    is_used = TRUE
+WHERE
+    username = @username
+    AND email = @email
+    AND is_used = FALSE;
\ No newline at end of file
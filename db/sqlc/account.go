@@ -0,0 +1,14 @@
+package db
+
+const (
+	// SystemUsername owns the internal accounts the bank books against
+	SystemUsername = "gobank"
+
+	AccountKindCustomer = "customer"
+	AccountKindCash     = "cash"
+)
+
+// IsSystem reports whether the account is an internal account of the bank
+func (account Account) IsSystem() bool {
+	return account.Kind != AccountKindCustomer
+}
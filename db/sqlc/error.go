@@ -0,0 +1,26 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	ForeignKeyViolation = "23503"
+	UniqueViolation     = "23505"
+	CheckViolation      = "23514"
+)
+
+// ErrRecordNotFound is returned by :one queries that match no rows
+var ErrRecordNotFound = pgx.ErrNoRows
+
+// ErrorCode returns the postgres error code of err, or an empty string if err is not a postgres error
+func ErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
@@ -8,8 +8,21 @@ import (
 )
 
 // execTx executes a function within a database transaction
-func (store *Store) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, pgx.TxOptions{})
+func (store *SQLStore) execTx(ctx context.Context, fn func(Querier) error) error {
+	return store.execTxWithOptions(ctx, pgx.TxOptions{}, fn)
+}
+
+// execReadOnlyTx executes a function within a read-only transaction, which reads a single
+// snapshot of the database without taking any row lock
+func (store *SQLStore) execReadOnlyTx(ctx context.Context, fn func(Querier) error) error {
+	return store.execTxWithOptions(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	}, fn)
+}
+
+func (store *SQLStore) execTxWithOptions(ctx context.Context, options pgx.TxOptions, fn func(Querier) error) error {
+	tx, err := store.db.BeginTx(ctx, options)
 	if err != nil {
 		return err
 	}
@@ -22,4 +35,4 @@ func (store *Store) execTx(ctx context.Context, fn func(*Queries) error) error {
 		return err
 	}
 	return tx.Commit(ctx)
-}
\ No newline at end of file
+}
@@ -0,0 +1,166 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: failed_login.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addUserFailedLoginAttempt = `-- name: AddUserFailedLoginAttempt :one
+UPDATE users
+SET
+    failed_login_attempts = failed_login_attempts + 1
+WHERE
+    username = $1
+RETURNING username, password, fullname, email, password_changed_at, created_at, is_email_verified, role, failed_login_attempts, locked_until, pending_email, locale, pending_email_expires_at
+`
+
+func (q *Queries) AddUserFailedLoginAttempt(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRow(ctx, addUserFailedLoginAttempt, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Password,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.PendingEmail,
+		&i.Locale,
+		&i.PendingEmailExpiresAt,
+	)
+	return i, err
+}
+
+const countFailedLoginsByClientIp = `-- name: CountFailedLoginsByClientIp :one
+SELECT count(*) FROM failed_logins
+WHERE
+    client_ip = $1
+    AND created_at > $2
+`
+
+type CountFailedLoginsByClientIpParams struct {
+	ClientIp string             `json:"client_ip"`
+	Since    pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) CountFailedLoginsByClientIp(ctx context.Context, arg CountFailedLoginsByClientIpParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countFailedLoginsByClientIp, arg.ClientIp, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createFailedLogin = `-- name: CreateFailedLogin :one
+INSERT INTO failed_logins (
+    username,
+    client_ip
+) VALUES (
+    $1, $2
+) RETURNING id, username, client_ip, created_at
+`
+
+type CreateFailedLoginParams struct {
+	Username string `json:"username"`
+	ClientIp string `json:"client_ip"`
+}
+
+func (q *Queries) CreateFailedLogin(ctx context.Context, arg CreateFailedLoginParams) (FailedLogin, error) {
+	row := q.db.QueryRow(ctx, createFailedLogin, arg.Username, arg.ClientIp)
+	var i FailedLogin
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.ClientIp,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteFailedLoginsBefore = `-- name: DeleteFailedLoginsBefore :execrows
+DELETE FROM failed_logins
+WHERE created_at < $1
+`
+
+func (q *Queries) DeleteFailedLoginsBefore(ctx context.Context, before pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteFailedLoginsBefore, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const lockUser = `-- name: LockUser :one
+UPDATE users
+SET
+    locked_until = $1
+WHERE
+    username = $2
+RETURNING username, password, fullname, email, password_changed_at, created_at, is_email_verified, role, failed_login_attempts, locked_until, pending_email, locale, pending_email_expires_at
+`
+
+type LockUserParams struct {
+	LockedUntil pgtype.Timestamptz `json:"locked_until"`
+	Username    string             `json:"username"`
+}
+
+func (q *Queries) LockUser(ctx context.Context, arg LockUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, lockUser, arg.LockedUntil, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Password,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.PendingEmail,
+		&i.Locale,
+		&i.PendingEmailExpiresAt,
+	)
+	return i, err
+}
+
+const unlockUser = `-- name: UnlockUser :one
+UPDATE users
+SET
+    failed_login_attempts = 0,
+    locked_until = NULL
+WHERE
+    username = $1
+RETURNING username, password, fullname, email, password_changed_at, created_at, is_email_verified, role, failed_login_attempts, locked_until, pending_email, locale, pending_email_expires_at
+`
+
+func (q *Queries) UnlockUser(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRow(ctx, unlockUser, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Password,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.PendingEmail,
+		&i.Locale,
+		&i.PendingEmailExpiresAt,
+	)
+	return i, err
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const AccountKindFx = "fx"
+
+var (
+	// ErrNoFxRate is returned when no exchange rate is in effect for a currency pair
+	ErrNoFxRate = errors.New("no exchange rate in effect")
+	// ErrAmountTooSmall is returned when an amount converts to less than one minor unit
+	ErrAmountTooSmall = errors.New("amount too small to convert")
+)
+
+// ConvertAmount converts an amount at rate, rounding half away from zero to the nearest minor unit
+func ConvertAmount(amount int64, rate pgtype.Numeric) (int64, error) {
+	if !rate.Valid || rate.NaN || rate.InfinityModifier != pgtype.Finite || rate.Int == nil {
+		return 0, fmt.Errorf("invalid exchange rate")
+	}
+
+	converted := new(big.Rat).SetInt(new(big.Int).Mul(big.NewInt(amount), rate.Int))
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(rate.Exp))), nil)
+	if rate.Exp < 0 {
+		converted.Quo(converted, new(big.Rat).SetInt(scale))
+	} else {
+		converted.Mul(converted, new(big.Rat).SetInt(scale))
+	}
+
+	// round half away from zero: add or subtract 1/2, then truncate
+	half := big.NewRat(1, 2)
+	if converted.Sign() < 0 {
+		converted.Sub(converted, half)
+	} else {
+		converted.Add(converted, half)
+	}
+	result := new(big.Int).Quo(converted.Num(), converted.Denom())
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("converted amount overflows")
+	}
+	return result.Int64(), nil
+}
+
+func abs(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,128 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: fx_rate.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createFxRate = `-- name: CreateFxRate :one
+INSERT INTO fx_rates (
+  from_currency,
+  to_currency,
+  rate,
+  effective_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, from_currency, to_currency, rate, effective_at, created_at
+`
+
+type CreateFxRateParams struct {
+	FromCurrency string             `json:"from_currency"`
+	ToCurrency   string             `json:"to_currency"`
+	Rate         pgtype.Numeric     `json:"rate"`
+	EffectiveAt  pgtype.Timestamptz `json:"effective_at"`
+}
+
+func (q *Queries) CreateFxRate(ctx context.Context, arg CreateFxRateParams) (FxRate, error) {
+	row := q.db.QueryRow(ctx, createFxRate,
+		arg.FromCurrency,
+		arg.ToCurrency,
+		arg.Rate,
+		arg.EffectiveAt,
+	)
+	var i FxRate
+	err := row.Scan(
+		&i.ID,
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.Rate,
+		&i.EffectiveAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getFxRate = `-- name: GetFxRate :one
+SELECT id, from_currency, to_currency, rate, effective_at, created_at FROM fx_rates
+WHERE
+    from_currency = $1
+    AND to_currency = $2
+    AND effective_at <= $3
+ORDER BY effective_at DESC, id DESC
+LIMIT 1
+`
+
+type GetFxRateParams struct {
+	FromCurrency string             `json:"from_currency"`
+	ToCurrency   string             `json:"to_currency"`
+	At           pgtype.Timestamptz `json:"at"`
+}
+
+func (q *Queries) GetFxRate(ctx context.Context, arg GetFxRateParams) (FxRate, error) {
+	row := q.db.QueryRow(ctx, getFxRate, arg.FromCurrency, arg.ToCurrency, arg.At)
+	var i FxRate
+	err := row.Scan(
+		&i.ID,
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.Rate,
+		&i.EffectiveAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listFxRates = `-- name: ListFxRates :many
+SELECT id, from_currency, to_currency, rate, effective_at, created_at FROM fx_rates
+WHERE
+    from_currency = $1
+    AND to_currency = $2
+ORDER BY effective_at DESC, id DESC
+LIMIT $3
+OFFSET $4
+`
+
+type ListFxRatesParams struct {
+	FromCurrency string `json:"from_currency"`
+	ToCurrency   string `json:"to_currency"`
+	Limit        int32  `json:"limit"`
+	Offset       int32  `json:"offset"`
+}
+
+func (q *Queries) ListFxRates(ctx context.Context, arg ListFxRatesParams) ([]FxRate, error) {
+	rows, err := q.db.Query(ctx, listFxRates,
+		arg.FromCurrency,
+		arg.ToCurrency,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FxRate{}
+	for rows.Next() {
+		var i FxRate
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromCurrency,
+			&i.ToCurrency,
+			&i.Rate,
+			&i.EffectiveAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,126 @@
+package db
+
+import "context"
+
+func (q *memoryQueries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.users[arg.Owner]; !ok {
+		return Account{}, foreignKeyViolation("accounts", "accounts_owner_fkey")
+	}
+	for _, account := range data.accounts {
+		if account.Owner == arg.Owner && account.Currency == arg.Currency && account.Kind == AccountKindCustomer {
+			return Account{}, uniqueViolation("accounts", "owner_currency_kind_key")
+		}
+	}
+
+	account := Account{
+		ID:        data.nextID("accounts"),
+		Owner:     arg.Owner,
+		Balance:   arg.Balance,
+		Currency:  arg.Currency,
+		CreatedAt: now(),
+		Kind:      AccountKindCustomer,
+	}
+	data.accounts[account.ID] = account
+	return account, nil
+}
+
+func (q *memoryQueries) GetAccount(ctx context.Context, id int64) (Account, error) {
+	data, done := q.begin()
+	defer done()
+
+	account, ok := data.accounts[id]
+	if !ok {
+		return Account{}, ErrRecordNotFound
+	}
+	return account, nil
+}
+
+func (q *memoryQueries) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
+	return q.GetAccount(ctx, id)
+}
+
+func (q *memoryQueries) GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (Account, error) {
+	data, done := q.begin()
+	defer done()
+
+	accounts := rows(data.accounts, func(account Account) bool {
+		return account.Owner == SystemUsername && account.Kind == arg.Kind && account.Currency == arg.Currency
+	})
+	if len(accounts) == 0 {
+		return Account{}, ErrRecordNotFound
+	}
+	return accounts[0], nil
+}
+
+func (q *memoryQueries) ListAccount(ctx context.Context, arg ListAccountParams) ([]Account, error) {
+	data, done := q.begin()
+	defer done()
+
+	accounts := rows(data.accounts, func(account Account) bool {
+		return account.Owner == arg.Owner
+	})
+	return paginate(accounts, arg.Limit, arg.Offset), nil
+}
+
+func (q *memoryQueries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
+	data, done := q.begin()
+	defer done()
+
+	account, ok := data.accounts[arg.ID]
+	if !ok {
+		return Account{}, ErrRecordNotFound
+	}
+	account.Balance = arg.Balance
+	data.accounts[account.ID] = account
+	return account, nil
+}
+
+func (q *memoryQueries) AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error) {
+	data, done := q.begin()
+	defer done()
+
+	account, ok := data.accounts[arg.ID]
+	if !ok {
+		return Account{}, ErrRecordNotFound
+	}
+	account.Balance += arg.Amount
+	data.accounts[account.ID] = account
+	return account, nil
+}
+
+func (q *memoryQueries) DeleteAccount(ctx context.Context, id int64) error {
+	data, done := q.begin()
+	defer done()
+
+	for _, entry := range data.entries {
+		if entry.AccountID == id {
+			return foreignKeyViolation("entries", "entries_account_id_fkey")
+		}
+	}
+	for _, transfer := range data.transfers {
+		if transfer.FromAccountID == id || transfer.ToAccountID == id {
+			return foreignKeyViolation("transfers", "transfers_from_account_id_fkey")
+		}
+	}
+	delete(data.accounts, id)
+	return nil
+}
+
+func (q *memoryQueries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	data, done := q.begin()
+	defer done()
+
+	account, ok := data.accounts[arg.ID]
+	if !ok {
+		return Account{}, ErrRecordNotFound
+	}
+	if arg.OverdraftLimit < 0 {
+		return Account{}, checkViolation("accounts", "overdraft_limit_non_negative")
+	}
+	account.OverdraftLimit = arg.OverdraftLimit
+	data.accounts[account.ID] = account
+	return account, nil
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"slices"
+)
+
+func (q *memoryQueries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.accounts[arg.AccountID]; !ok {
+		return Entry{}, foreignKeyViolation("entries", "entries_account_id_fkey")
+	}
+
+	entry := Entry{
+		ID:        data.nextID("entries"),
+		AccountID: arg.AccountID,
+		Amount:    arg.Amount,
+		CreatedAt: now(),
+	}
+	data.entries[entry.ID] = entry
+	return entry, nil
+}
+
+func (q *memoryQueries) GetEntry(ctx context.Context, id int64) (Entry, error) {
+	data, done := q.begin()
+	defer done()
+
+	entry, ok := data.entries[id]
+	if !ok {
+		return Entry{}, ErrRecordNotFound
+	}
+	return entry, nil
+}
+
+func (q *memoryQueries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
+	data, done := q.begin()
+	defer done()
+
+	entries := rows(data.entries, func(entry Entry) bool {
+		return entry.AccountID == arg.AccountID
+	})
+	return paginate(entries, arg.Limit, arg.Offset), nil
+}
+
+func (q *memoryQueries) ListEntriesBetween(ctx context.Context, arg ListEntriesBetweenParams) ([]Entry, error) {
+	data, done := q.begin()
+	defer done()
+
+	entries := rows(data.entries, func(entry Entry) bool {
+		return entry.AccountID == arg.AccountID &&
+			!entry.CreatedAt.Time.Before(arg.StartTime.Time) &&
+			entry.CreatedAt.Time.Before(arg.EndTime.Time)
+	})
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		return a.CreatedAt.Time.Compare(b.CreatedAt.Time)
+	})
+	return entries, nil
+}
+
+func (q *memoryQueries) SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error) {
+	data, done := q.begin()
+	defer done()
+
+	var total int64
+	for _, entry := range data.entries {
+		if entry.AccountID == arg.AccountID && !entry.CreatedAt.Time.Before(arg.Since.Time) {
+			total += entry.Amount
+		}
+	}
+	return total, nil
+}
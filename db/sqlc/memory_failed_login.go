@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (q *memoryQueries) CreateFailedLogin(ctx context.Context, arg CreateFailedLoginParams) (FailedLogin, error) {
+	data, done := q.begin()
+	defer done()
+
+	failedLogin := FailedLogin{
+		ID:        data.nextID("failed_logins"),
+		Username:  arg.Username,
+		ClientIp:  arg.ClientIp,
+		CreatedAt: now(),
+	}
+	data.failedLogins[failedLogin.ID] = failedLogin
+	return failedLogin, nil
+}
+
+func (q *memoryQueries) CountFailedLoginsByClientIp(ctx context.Context, arg CountFailedLoginsByClientIpParams) (int64, error) {
+	data, done := q.begin()
+	defer done()
+
+	matches := rows(data.failedLogins, func(failedLogin FailedLogin) bool {
+		return failedLogin.ClientIp == arg.ClientIp && failedLogin.CreatedAt.Time.After(arg.Since.Time)
+	})
+	return int64(len(matches)), nil
+}
+
+func (q *memoryQueries) DeleteFailedLoginsBefore(ctx context.Context, before pgtype.Timestamptz) (int64, error) {
+	data, done := q.begin()
+	defer done()
+
+	var deleted int64
+	for id, failedLogin := range data.failedLogins {
+		if failedLogin.CreatedAt.Time.Before(before.Time) {
+			delete(data.failedLogins, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (q *memoryQueries) AddUserFailedLoginAttempt(ctx context.Context, username string) (User, error) {
+	data, done := q.begin()
+	defer done()
+
+	user, ok := data.users[username]
+	if !ok {
+		return User{}, ErrRecordNotFound
+	}
+	user.FailedLoginAttempts++
+	data.users[user.Username] = user
+	return user, nil
+}
+
+func (q *memoryQueries) LockUser(ctx context.Context, arg LockUserParams) (User, error) {
+	data, done := q.begin()
+	defer done()
+
+	user, ok := data.users[arg.Username]
+	if !ok {
+		return User{}, ErrRecordNotFound
+	}
+	user.LockedUntil = arg.LockedUntil
+	data.users[user.Username] = user
+	return user, nil
+}
+
+func (q *memoryQueries) UnlockUser(ctx context.Context, username string) (User, error) {
+	data, done := q.begin()
+	defer done()
+
+	user, ok := data.users[username]
+	if !ok {
+		return User{}, ErrRecordNotFound
+	}
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = pgtype.Timestamptz{}
+	data.users[user.Username] = user
+	return user, nil
+}
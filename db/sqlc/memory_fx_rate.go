@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"slices"
+)
+
+func (q *memoryQueries) CreateFxRate(ctx context.Context, arg CreateFxRateParams) (FxRate, error) {
+	data, done := q.begin()
+	defer done()
+
+	if !arg.Rate.Valid || arg.Rate.Int == nil || arg.Rate.Int.Sign() <= 0 {
+		return FxRate{}, checkViolation("fx_rates", "rate_positive")
+	}
+
+	fxRate := FxRate{
+		ID:           data.nextID("fx_rates"),
+		FromCurrency: arg.FromCurrency,
+		ToCurrency:   arg.ToCurrency,
+		Rate:         arg.Rate,
+		EffectiveAt:  arg.EffectiveAt,
+		CreatedAt:    now(),
+	}
+	data.fxRates[fxRate.ID] = fxRate
+	return fxRate, nil
+}
+
+func (q *memoryQueries) GetFxRate(ctx context.Context, arg GetFxRateParams) (FxRate, error) {
+	data, done := q.begin()
+	defer done()
+
+	fxRates := latestFxRates(data, arg.FromCurrency, arg.ToCurrency)
+	for _, fxRate := range fxRates {
+		if !fxRate.EffectiveAt.Time.After(arg.At.Time) {
+			return fxRate, nil
+		}
+	}
+	return FxRate{}, ErrRecordNotFound
+}
+
+func (q *memoryQueries) ListFxRates(ctx context.Context, arg ListFxRatesParams) ([]FxRate, error) {
+	data, done := q.begin()
+	defer done()
+
+	return paginate(latestFxRates(data, arg.FromCurrency, arg.ToCurrency), arg.Limit, arg.Offset), nil
+}
+
+// latestFxRates returns the rates of a currency pair ordered by effective_at DESC, id DESC
+func latestFxRates(data *memoryData, fromCurrency string, toCurrency string) []FxRate {
+	fxRates := rows(data.fxRates, func(fxRate FxRate) bool {
+		return fxRate.FromCurrency == fromCurrency && fxRate.ToCurrency == toCurrency
+	})
+	slices.Reverse(fxRates)
+	slices.SortStableFunc(fxRates, func(a, b FxRate) int {
+		return b.EffectiveAt.Time.Compare(a.EffectiveAt.Time)
+	})
+	return fxRates
+}
@@ -0,0 +1,56 @@
+package db
+
+import "context"
+
+type idempotencyKeyID struct {
+	username string
+	key      string
+}
+
+func (q *memoryQueries) CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.users[arg.Username]; !ok {
+		return IdempotencyKey{}, foreignKeyViolation("idempotency_keys", "idempotency_keys_username_fkey")
+	}
+	id := idempotencyKeyID{username: arg.Username, key: arg.Key}
+	if _, ok := data.idempotency[id]; ok {
+		// ON CONFLICT DO NOTHING returns no row
+		return IdempotencyKey{}, ErrRecordNotFound
+	}
+
+	key := IdempotencyKey{
+		Username:    arg.Username,
+		Key:         arg.Key,
+		RequestHash: arg.RequestHash,
+		CreatedAt:   now(),
+	}
+	data.idempotency[id] = key
+	return key, nil
+}
+
+func (q *memoryQueries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	data, done := q.begin()
+	defer done()
+
+	key, ok := data.idempotency[idempotencyKeyID{username: arg.Username, key: arg.Key}]
+	if !ok {
+		return IdempotencyKey{}, ErrRecordNotFound
+	}
+	return key, nil
+}
+
+func (q *memoryQueries) UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) (IdempotencyKey, error) {
+	data, done := q.begin()
+	defer done()
+
+	id := idempotencyKeyID{username: arg.Username, key: arg.Key}
+	key, ok := data.idempotency[id]
+	if !ok {
+		return IdempotencyKey{}, ErrRecordNotFound
+	}
+	key.Response = arg.Response
+	data.idempotency[id] = key
+	return key, nil
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+type notificationPreferenceID struct {
+	username string
+	channel  string
+}
+
+func (q *memoryQueries) UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) (NotificationPreference, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.users[arg.Username]; !ok {
+		return NotificationPreference{}, foreignKeyViolation("notification_preferences", "notification_preferences_username_fkey")
+	}
+
+	preference := NotificationPreference{
+		Username:              arg.Username,
+		Channel:               arg.Channel,
+		TransferNotifications: arg.TransferNotifications,
+		UpdatedAt:             now(),
+	}
+	data.notificationPreferences[notificationPreferenceID{username: arg.Username, channel: arg.Channel}] = preference
+	return preference, nil
+}
+
+func (q *memoryQueries) GetNotificationPreference(ctx context.Context, arg GetNotificationPreferenceParams) (NotificationPreference, error) {
+	data, done := q.begin()
+	defer done()
+
+	preference, ok := data.notificationPreferences[notificationPreferenceID{username: arg.Username, channel: arg.Channel}]
+	if !ok {
+		return NotificationPreference{}, ErrRecordNotFound
+	}
+	return preference, nil
+}
+
+func (q *memoryQueries) ListNotificationPreferences(ctx context.Context, username string) ([]NotificationPreference, error) {
+	data, done := q.begin()
+	defer done()
+
+	preferences := []NotificationPreference{}
+	for id, preference := range data.notificationPreferences {
+		if id.username == username {
+			preferences = append(preferences, preference)
+		}
+	}
+	slices.SortFunc(preferences, func(a, b NotificationPreference) int {
+		return strings.Compare(a.Channel, b.Channel)
+	})
+	return preferences, nil
+}
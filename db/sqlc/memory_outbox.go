@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"slices"
+)
+
+func (q *memoryQueries) CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) (OutboxMessage, error) {
+	data, done := q.begin()
+	defer done()
+
+	message := OutboxMessage{
+		ID:        data.nextID("outbox"),
+		TaskType:  arg.TaskType,
+		Payload:   slices.Clone(arg.Payload),
+		Queue:     arg.Queue,
+		MaxRetry:  arg.MaxRetry,
+		CreatedAt: now(),
+	}
+	data.outbox[message.ID] = message
+	return message, nil
+}
+
+func (q *memoryQueries) ListUnsentOutboxMessages(ctx context.Context, limit int32) ([]OutboxMessage, error) {
+	data, done := q.begin()
+	defer done()
+
+	messages := rows(data.outbox, func(message OutboxMessage) bool {
+		return !message.SentAt.Valid
+	})
+	return paginate(messages, limit, 0), nil
+}
+
+func (q *memoryQueries) MarkOutboxMessageSent(ctx context.Context, id int64) (OutboxMessage, error) {
+	data, done := q.begin()
+	defer done()
+
+	message, ok := data.outbox[id]
+	if !ok || message.SentAt.Valid {
+		return OutboxMessage{}, ErrRecordNotFound
+	}
+	message.SentAt = now()
+	data.outbox[message.ID] = message
+	return message, nil
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (q *memoryQueries) CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.users[arg.Username]; !ok {
+		return PasswordReset{}, foreignKeyViolation("password_resets", "password_resets_username_fkey")
+	}
+
+	createdAt := now()
+	passwordReset := PasswordReset{
+		ID:         data.nextID("password_resets"),
+		Username:   arg.Username,
+		Email:      arg.Email,
+		SecretCode: arg.SecretCode,
+		CreatedAt:  createdAt,
+		ExpiredAt:  pgtype.Timestamptz{Time: createdAt.Time.Add(15 * time.Minute), Valid: true},
+	}
+	data.passwordResets[passwordReset.ID] = passwordReset
+	return passwordReset, nil
+}
+
+func (q *memoryQueries) UsePasswordReset(ctx context.Context, arg UsePasswordResetParams) (PasswordReset, error) {
+	data, done := q.begin()
+	defer done()
+
+	passwordReset, ok := data.passwordResets[arg.ID]
+	if !ok || passwordReset.SecretCode != arg.SecretCode || passwordReset.IsUsed || !passwordReset.ExpiredAt.Time.After(time.Now()) {
+		return PasswordReset{}, ErrRecordNotFound
+	}
+	passwordReset.IsUsed = true
+	data.passwordResets[passwordReset.ID] = passwordReset
+	return passwordReset, nil
+}
+
+func (q *memoryQueries) InvalidatePasswordResets(ctx context.Context, username string) error {
+	data, done := q.begin()
+	defer done()
+
+	for id, passwordReset := range data.passwordResets {
+		if passwordReset.Username == username && !passwordReset.IsUsed {
+			passwordReset.IsUsed = true
+			data.passwordResets[id] = passwordReset
+		}
+	}
+	return nil
+}
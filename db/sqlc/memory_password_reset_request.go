@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (q *memoryQueries) CreatePasswordResetRequest(ctx context.Context, arg CreatePasswordResetRequestParams) (PasswordResetRequest, error) {
+	data, done := q.begin()
+	defer done()
+
+	request := PasswordResetRequest{
+		ID:        data.nextID("password_reset_requests"),
+		Email:     arg.Email,
+		ClientIp:  arg.ClientIp,
+		LinkSent:  arg.LinkSent,
+		CreatedAt: now(),
+	}
+	data.passwordResetRequests[request.ID] = request
+	return request, nil
+}
+
+func (q *memoryQueries) CountPasswordResetRequestsByClientIp(ctx context.Context, arg CountPasswordResetRequestsByClientIpParams) (int64, error) {
+	data, done := q.begin()
+	defer done()
+
+	matches := rows(data.passwordResetRequests, func(request PasswordResetRequest) bool {
+		return request.ClientIp == arg.ClientIp && request.CreatedAt.Time.After(arg.Since.Time)
+	})
+	return int64(len(matches)), nil
+}
+
+func (q *memoryQueries) CountSentPasswordResetRequests(ctx context.Context, arg CountSentPasswordResetRequestsParams) (int64, error) {
+	data, done := q.begin()
+	defer done()
+
+	matches := rows(data.passwordResetRequests, func(request PasswordResetRequest) bool {
+		return request.Email == arg.Email && request.LinkSent && request.CreatedAt.Time.After(arg.Since.Time)
+	})
+	return int64(len(matches)), nil
+}
+
+func (q *memoryQueries) DeletePasswordResetRequestsBefore(ctx context.Context, before pgtype.Timestamptz) (int64, error) {
+	data, done := q.begin()
+	defer done()
+
+	var deleted int64
+	for id, request := range data.passwordResetRequests {
+		if request.CreatedAt.Time.Before(before.Time) {
+			delete(data.passwordResetRequests, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"slices"
+)
+
+func (q *memoryQueries) CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error) {
+	data, done := q.begin()
+	defer done()
+
+	if arg.Amount <= 0 {
+		return ScheduledTransfer{}, checkViolation("scheduled_transfers", "scheduled_amount_positive")
+	}
+	if _, ok := data.users[arg.Owner]; !ok {
+		return ScheduledTransfer{}, foreignKeyViolation("scheduled_transfers", "scheduled_transfers_owner_fkey")
+	}
+	if _, ok := data.accounts[arg.FromAccountID]; !ok {
+		return ScheduledTransfer{}, foreignKeyViolation("scheduled_transfers", "scheduled_transfers_from_account_id_fkey")
+	}
+	if _, ok := data.accounts[arg.ToAccountID]; !ok {
+		return ScheduledTransfer{}, foreignKeyViolation("scheduled_transfers", "scheduled_transfers_to_account_id_fkey")
+	}
+
+	scheduledTransfer := ScheduledTransfer{
+		ID:             data.nextID("scheduled_transfers"),
+		Owner:          arg.Owner,
+		FromAccountID:  arg.FromAccountID,
+		ToAccountID:    arg.ToAccountID,
+		Amount:         arg.Amount,
+		Frequency:      arg.Frequency,
+		CronExpression: arg.CronExpression,
+		Status:         ScheduleStatusActive,
+		StartAt:        arg.StartAt,
+		NextRunAt:      arg.NextRunAt,
+		CreatedAt:      now(),
+	}
+	data.scheduled[scheduledTransfer.ID] = scheduledTransfer
+	return scheduledTransfer, nil
+}
+
+func (q *memoryQueries) GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error) {
+	data, done := q.begin()
+	defer done()
+
+	scheduledTransfer, ok := data.scheduled[id]
+	if !ok {
+		return ScheduledTransfer{}, ErrRecordNotFound
+	}
+	return scheduledTransfer, nil
+}
+
+func (q *memoryQueries) GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error) {
+	return q.GetScheduledTransfer(ctx, id)
+}
+
+func (q *memoryQueries) ListScheduledTransfers(ctx context.Context, arg ListScheduledTransfersParams) ([]ScheduledTransfer, error) {
+	data, done := q.begin()
+	defer done()
+
+	scheduledTransfers := rows(data.scheduled, func(scheduledTransfer ScheduledTransfer) bool {
+		return scheduledTransfer.Owner == arg.Owner
+	})
+	return paginate(scheduledTransfers, arg.Limit, arg.Offset), nil
+}
+
+func (q *memoryQueries) ListDueScheduledTransfers(ctx context.Context, arg ListDueScheduledTransfersParams) ([]ScheduledTransfer, error) {
+	data, done := q.begin()
+	defer done()
+
+	scheduledTransfers := rows(data.scheduled, func(scheduledTransfer ScheduledTransfer) bool {
+		return scheduledTransfer.Status == ScheduleStatusActive && !scheduledTransfer.NextRunAt.Time.After(arg.Now.Time)
+	})
+	slices.SortStableFunc(scheduledTransfers, func(a, b ScheduledTransfer) int {
+		return a.NextRunAt.Time.Compare(b.NextRunAt.Time)
+	})
+	return paginate(scheduledTransfers, arg.LimitCount, 0), nil
+}
+
+func (q *memoryQueries) UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfer, error) {
+	data, done := q.begin()
+	defer done()
+
+	scheduledTransfer, ok := data.scheduled[arg.ID]
+	if !ok {
+		return ScheduledTransfer{}, ErrRecordNotFound
+	}
+	if arg.Amount.Valid {
+		if arg.Amount.Int64 <= 0 {
+			return ScheduledTransfer{}, checkViolation("scheduled_transfers", "scheduled_amount_positive")
+		}
+		scheduledTransfer.Amount = arg.Amount.Int64
+	}
+	if arg.Status.Valid {
+		scheduledTransfer.Status = arg.Status.String
+	}
+	if arg.NextRunAt.Valid {
+		scheduledTransfer.NextRunAt = arg.NextRunAt
+	}
+	if arg.LastRunAt.Valid {
+		scheduledTransfer.LastRunAt = arg.LastRunAt
+	}
+	if arg.FailureCount.Valid {
+		scheduledTransfer.FailureCount = arg.FailureCount.Int32
+	}
+	data.scheduled[scheduledTransfer.ID] = scheduledTransfer
+	return scheduledTransfer, nil
+}
+
+func (q *memoryQueries) CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.scheduled[arg.ScheduledTransferID]; !ok {
+		return ScheduledTransferRun{}, foreignKeyViolation("scheduled_transfer_runs", "scheduled_transfer_runs_scheduled_transfer_id_fkey")
+	}
+	if _, ok := data.transfers[arg.TransferID.Int64]; arg.TransferID.Valid && !ok {
+		return ScheduledTransferRun{}, foreignKeyViolation("scheduled_transfer_runs", "scheduled_transfer_runs_transfer_id_fkey")
+	}
+
+	run := ScheduledTransferRun{
+		ID:                  data.nextID("scheduled_transfer_runs"),
+		ScheduledTransferID: arg.ScheduledTransferID,
+		TransferID:          arg.TransferID,
+		Status:              arg.Status,
+		Error:               arg.Error,
+		ScheduledAt:         arg.ScheduledAt,
+		CreatedAt:           now(),
+	}
+	data.scheduledRuns[run.ID] = run
+	return run, nil
+}
+
+func (q *memoryQueries) ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error) {
+	data, done := q.begin()
+	defer done()
+
+	runs := rows(data.scheduledRuns, func(run ScheduledTransferRun) bool {
+		return run.ScheduledTransferID == arg.ScheduledTransferID
+	})
+	return paginate(runs, arg.Limit, arg.Offset), nil
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+func (q *memoryQueries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.users[arg.Username]; !ok {
+		return Session{}, foreignKeyViolation("sessions", "sessions_username_fkey")
+	}
+	if _, ok := data.sessions[arg.ID]; ok {
+		return Session{}, uniqueViolation("sessions", "sessions_pkey")
+	}
+
+	session := Session{
+		ID:           arg.ID,
+		Username:     arg.Username,
+		RefreshToken: arg.RefreshToken,
+		UserAgent:    arg.UserAgent,
+		ClientIp:     arg.ClientIp,
+		IsBlocked:    arg.IsBlocked,
+		ExpiresAt:    arg.ExpiresAt,
+		CreatedAt:    now(),
+	}
+	data.sessions[session.ID] = session
+	return session, nil
+}
+
+func (q *memoryQueries) GetSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	data, done := q.begin()
+	defer done()
+
+	session, ok := data.sessions[id]
+	if !ok {
+		return Session{}, ErrRecordNotFound
+	}
+	return session, nil
+}
+
+func (q *memoryQueries) ListSessions(ctx context.Context, arg ListSessionsParams) ([]Session, error) {
+	data, done := q.begin()
+	defer done()
+
+	sessions := []Session{}
+	for _, session := range data.sessions {
+		if session.Username == arg.Username {
+			sessions = append(sessions, session)
+		}
+	}
+	slices.SortFunc(sessions, func(a, b Session) int {
+		if c := b.CreatedAt.Time.Compare(a.CreatedAt.Time); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	return paginate(sessions, arg.Limit, arg.Offset), nil
+}
+
+func (q *memoryQueries) BlockSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	data, done := q.begin()
+	defer done()
+
+	session, ok := data.sessions[id]
+	if !ok {
+		return Session{}, ErrRecordNotFound
+	}
+	session.IsBlocked = true
+	data.sessions[session.ID] = session
+	return session, nil
+}
+
+func (q *memoryQueries) BlockUserSessions(ctx context.Context, arg BlockUserSessionsParams) (int64, error) {
+	data, done := q.begin()
+	defer done()
+
+	var blocked int64
+	for id, session := range data.sessions {
+		if session.Username == arg.Username && id != arg.ExceptID && !session.IsBlocked {
+			session.IsBlocked = true
+			data.sessions[id] = session
+			blocked++
+		}
+	}
+	return blocked, nil
+}
+
+func (q *memoryQueries) RotateSessionRefreshToken(ctx context.Context, arg RotateSessionRefreshTokenParams) (Session, error) {
+	data, done := q.begin()
+	defer done()
+
+	session, ok := data.sessions[arg.ID]
+	if !ok || session.RefreshToken != arg.RefreshToken || session.IsBlocked {
+		return Session{}, ErrRecordNotFound
+	}
+	session.RefreshToken = arg.NewRefreshToken
+	session.ExpiresAt = arg.ExpiresAt
+	data.sessions[session.ID] = session
+	return session, nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+func (q *memoryQueries) CreateSigningKey(ctx context.Context, arg CreateSigningKeyParams) (SigningKey, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.signingKeys[arg.Kid]; ok {
+		return SigningKey{}, uniqueViolation("signing_keys", "signing_keys_pkey")
+	}
+
+	signingKey := SigningKey{
+		Kid:       arg.Kid,
+		Algorithm: arg.Algorithm,
+		Key:       slices.Clone(arg.Key),
+		CreatedAt: now(),
+		Encrypted: true,
+	}
+	data.signingKeys[signingKey.Kid] = signingKey
+	return signingKey, nil
+}
+
+func (q *memoryQueries) EncryptSigningKey(ctx context.Context, arg EncryptSigningKeyParams) (SigningKey, error) {
+	data, done := q.begin()
+	defer done()
+
+	signingKey, ok := data.signingKeys[arg.Kid]
+	if !ok || signingKey.Encrypted {
+		return SigningKey{}, ErrRecordNotFound
+	}
+	signingKey.Key = slices.Clone(arg.Key)
+	signingKey.Encrypted = true
+	data.signingKeys[signingKey.Kid] = signingKey
+	return signingKey, nil
+}
+
+func (q *memoryQueries) ListSigningKeys(ctx context.Context, arg ListSigningKeysParams) ([]SigningKey, error) {
+	data, done := q.begin()
+	defer done()
+
+	signingKeys := rows(data.signingKeys, func(signingKey SigningKey) bool {
+		return signingKey.Algorithm == arg.Algorithm &&
+			(!signingKey.RetiresAt.Valid || signingKey.RetiresAt.Time.After(arg.Now.Time))
+	})
+	slices.SortStableFunc(signingKeys, func(a, b SigningKey) int {
+		if c := b.CreatedAt.Time.Compare(a.CreatedAt.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Kid, b.Kid)
+	})
+	return signingKeys, nil
+}
+
+func (q *memoryQueries) RetireSigningKeys(ctx context.Context, arg RetireSigningKeysParams) ([]SigningKey, error) {
+	data, done := q.begin()
+	defer done()
+
+	retired := rows(data.signingKeys, func(signingKey SigningKey) bool {
+		return signingKey.Algorithm == arg.Algorithm && signingKey.Kid != arg.ExceptKid &&
+			(!signingKey.RetiresAt.Valid || signingKey.RetiresAt.Time.After(arg.RetiresAt.Time))
+	})
+	for i := range retired {
+		retired[i].RetiresAt = arg.RetiresAt
+		data.signingKeys[retired[i].Kid] = retired[i]
+	}
+	return retired, nil
+}
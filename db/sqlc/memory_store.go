@@ -0,0 +1,252 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// MemoryStore is an in-memory Store for tests. It enforces the same keys and
+// constraints as the postgres schema, and runs every transaction serially on a
+// copy of the data that is only kept if the transaction succeeds.
+type MemoryStore struct {
+	*memoryQueries
+	txStore
+	mu   sync.Mutex
+	data *memoryData
+}
+
+// NewMemoryStore creates a new in-memory store holding the same seed data as the migrations
+func NewMemoryStore() Store {
+	data := newMemoryData()
+	data.seed()
+
+	store := &MemoryStore{
+		data: data,
+	}
+	store.memoryQueries = &memoryQueries{store: store}
+	store.txStore = txStore{execTx: store.execTx, execReadOnlyTx: store.execReadOnlyTx}
+	return store
+}
+
+// execTx executes a function within an in-memory transaction
+func (store *MemoryStore) execTx(ctx context.Context, fn func(Querier) error) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	data := store.data.clone()
+	err := fn(&memoryQueries{store: store, tx: data})
+	if err != nil {
+		return err
+	}
+	store.data = data
+	return nil
+}
+
+// execReadOnlyTx executes a function on a snapshot of the data, whose changes are always dropped
+func (store *MemoryStore) execReadOnlyTx(ctx context.Context, fn func(Querier) error) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return fn(&memoryQueries{store: store, tx: store.data.clone()})
+}
+
+type memoryData struct {
+	sequences               map[string]int64
+	accounts                map[int64]Account
+	entries                 map[int64]Entry
+	failedLogins            map[int64]FailedLogin
+	fxRates                 map[int64]FxRate
+	idempotency             map[idempotencyKeyID]IdempotencyKey
+	notificationPreferences map[notificationPreferenceID]NotificationPreference
+	outbox                  map[int64]OutboxMessage
+	passwordResets          map[int64]PasswordReset
+	passwordResetRequests   map[int64]PasswordResetRequest
+	challenges              map[uuid.UUID]LoginChallenge
+	recoveryCodes           map[int64]RecoveryCode
+	scheduled               map[int64]ScheduledTransfer
+	scheduledRuns           map[int64]ScheduledTransferRun
+	sessions                map[uuid.UUID]Session
+	signingKeys             map[string]SigningKey
+	totp                    map[string]TOTPCredential
+	transfers               map[int64]Transfer
+	users                   map[string]User
+	verifyEmails            map[int64]VerifyEmail
+	webhookEndpoints        map[int64]WebhookEndpoint
+	webhookDeliveries       map[int64]WebhookDelivery
+}
+
+func newMemoryData() *memoryData {
+	return &memoryData{
+		sequences:               map[string]int64{},
+		accounts:                map[int64]Account{},
+		entries:                 map[int64]Entry{},
+		failedLogins:            map[int64]FailedLogin{},
+		fxRates:                 map[int64]FxRate{},
+		idempotency:             map[idempotencyKeyID]IdempotencyKey{},
+		notificationPreferences: map[notificationPreferenceID]NotificationPreference{},
+		outbox:                  map[int64]OutboxMessage{},
+		passwordResets:          map[int64]PasswordReset{},
+		passwordResetRequests:   map[int64]PasswordResetRequest{},
+		challenges:              map[uuid.UUID]LoginChallenge{},
+		recoveryCodes:           map[int64]RecoveryCode{},
+		scheduled:               map[int64]ScheduledTransfer{},
+		scheduledRuns:           map[int64]ScheduledTransferRun{},
+		sessions:                map[uuid.UUID]Session{},
+		signingKeys:             map[string]SigningKey{},
+		totp:                    map[string]TOTPCredential{},
+		transfers:               map[int64]Transfer{},
+		users:                   map[string]User{},
+		verifyEmails:            map[int64]VerifyEmail{},
+		webhookEndpoints:        map[int64]WebhookEndpoint{},
+		webhookDeliveries:       map[int64]WebhookDelivery{},
+	}
+}
+
+func (data *memoryData) clone() *memoryData {
+	return &memoryData{
+		sequences:               maps.Clone(data.sequences),
+		accounts:                maps.Clone(data.accounts),
+		entries:                 maps.Clone(data.entries),
+		failedLogins:            maps.Clone(data.failedLogins),
+		fxRates:                 maps.Clone(data.fxRates),
+		idempotency:             maps.Clone(data.idempotency),
+		notificationPreferences: maps.Clone(data.notificationPreferences),
+		outbox:                  maps.Clone(data.outbox),
+		passwordResets:          maps.Clone(data.passwordResets),
+		passwordResetRequests:   maps.Clone(data.passwordResetRequests),
+		challenges:              maps.Clone(data.challenges),
+		recoveryCodes:           maps.Clone(data.recoveryCodes),
+		scheduled:               maps.Clone(data.scheduled),
+		scheduledRuns:           maps.Clone(data.scheduledRuns),
+		sessions:                maps.Clone(data.sessions),
+		signingKeys:             maps.Clone(data.signingKeys),
+		totp:                    maps.Clone(data.totp),
+		transfers:               maps.Clone(data.transfers),
+		users:                   maps.Clone(data.users),
+		verifyEmails:            maps.Clone(data.verifyEmails),
+		webhookEndpoints:        maps.Clone(data.webhookEndpoints),
+		webhookDeliveries:       maps.Clone(data.webhookDeliveries),
+	}
+}
+
+// seed inserts the rows the migrations insert
+func (data *memoryData) seed() {
+	data.users[SystemUsername] = User{
+		Username:          SystemUsername,
+		Fullname:          "Go Bank",
+		Email:             "system@gobank.internal",
+		PasswordChangedAt: pgtype.Timestamptz{Time: time.Time{}.UTC(), Valid: true},
+		CreatedAt:         now(),
+		IsEmailVerified:   true,
+		Role:              utils.DepositorRole,
+		Locale:            utils.DefaultLocale,
+	}
+	for _, kind := range []string{AccountKindCash, AccountKindFx} {
+		for _, currency := range []string{"USD", "EUR", "INR"} {
+			id := data.nextID("accounts")
+			data.accounts[id] = Account{
+				ID:        id,
+				Owner:     SystemUsername,
+				Currency:  currency,
+				CreatedAt: now(),
+				Kind:      kind,
+			}
+		}
+	}
+}
+
+// nextID returns the next value of the bigserial sequence of table
+func (data *memoryData) nextID(table string) int64 {
+	data.sequences[table]++
+	return data.sequences[table]
+}
+
+// memoryQueries implements Querier on the data of a MemoryStore. Outside of a
+// transaction every query locks the store and runs on the committed data.
+type memoryQueries struct {
+	store *MemoryStore
+	tx    *memoryData
+}
+
+var _ Querier = (*memoryQueries)(nil)
+
+func (q *memoryQueries) begin() (*memoryData, func()) {
+	if q.tx != nil {
+		return q.tx, func() {}
+	}
+	q.store.mu.Lock()
+	return q.store.data, q.store.mu.Unlock
+}
+
+// rows returns the values of m matching keep, ordered by key
+func rows[K cmp.Ordered, V any](m map[K]V, keep func(V) bool) []V {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	items := []V{}
+	for _, key := range keys {
+		if keep(m[key]) {
+			items = append(items, m[key])
+		}
+	}
+	return items
+}
+
+// paginate applies LIMIT and OFFSET to items
+func paginate[T any](items []T, limit int32, offset int32) []T {
+	if int(offset) >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if int(limit) < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+func now() pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: time.Now(), Valid: true}
+}
+
+func uniqueViolation(table string, constraint string) error {
+	return &pgconn.PgError{
+		Severity:       "ERROR",
+		Code:           UniqueViolation,
+		Message:        fmt.Sprintf("duplicate key value violates unique constraint %q", constraint),
+		TableName:      table,
+		ConstraintName: constraint,
+	}
+}
+
+func foreignKeyViolation(table string, constraint string) error {
+	return &pgconn.PgError{
+		Severity:       "ERROR",
+		Code:           ForeignKeyViolation,
+		Message:        fmt.Sprintf("insert or update on table %q violates foreign key constraint %q", table, constraint),
+		TableName:      table,
+		ConstraintName: constraint,
+	}
+}
+
+func checkViolation(table string, constraint string) error {
+	return &pgconn.PgError{
+		Severity:       "ERROR",
+		Code:           CheckViolation,
+		Message:        fmt.Sprintf("new row for relation %q violates check constraint %q", table, constraint),
+		TableName:      table,
+		ConstraintName: constraint,
+	}
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (q *memoryQueries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
+	return q.CreateFxTransfer(ctx, CreateFxTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+	})
+}
+
+func (q *memoryQueries) CreateFxTransfer(ctx context.Context, arg CreateFxTransferParams) (Transfer, error) {
+	data, done := q.begin()
+	defer done()
+
+	return insertTransfer(data, Transfer{
+		FromAccountID:    arg.FromAccountID,
+		ToAccountID:      arg.ToAccountID,
+		Amount:           arg.Amount,
+		ExchangeRate:     arg.ExchangeRate,
+		FxRateID:         arg.FxRateID,
+		SourceTransferID: arg.SourceTransferID,
+	})
+}
+
+func (q *memoryQueries) CreateReversalTransfer(ctx context.Context, arg CreateReversalTransferParams) (Transfer, error) {
+	data, done := q.begin()
+	defer done()
+
+	return insertTransfer(data, Transfer{
+		FromAccountID:      arg.FromAccountID,
+		ToAccountID:        arg.ToAccountID,
+		Amount:             arg.Amount,
+		ReversedTransferID: arg.ReversedTransferID,
+	})
+}
+
+// insertTransfer checks the foreign keys of transfer, and stores it with a new id
+func insertTransfer(data *memoryData, transfer Transfer) (Transfer, error) {
+	if _, ok := data.accounts[transfer.FromAccountID]; !ok {
+		return Transfer{}, foreignKeyViolation("transfers", "transfers_from_account_id_fkey")
+	}
+	if _, ok := data.accounts[transfer.ToAccountID]; !ok {
+		return Transfer{}, foreignKeyViolation("transfers", "transfers_to_account_id_fkey")
+	}
+	if _, ok := data.fxRates[transfer.FxRateID.Int64]; transfer.FxRateID.Valid && !ok {
+		return Transfer{}, foreignKeyViolation("transfers", "transfers_fx_rate_id_fkey")
+	}
+	if _, ok := data.transfers[transfer.SourceTransferID.Int64]; transfer.SourceTransferID.Valid && !ok {
+		return Transfer{}, foreignKeyViolation("transfers", "transfers_source_transfer_id_fkey")
+	}
+	if _, ok := data.transfers[transfer.ReversedTransferID.Int64]; transfer.ReversedTransferID.Valid && !ok {
+		return Transfer{}, foreignKeyViolation("transfers", "transfers_reversed_transfer_id_fkey")
+	}
+
+	transfer.ID = data.nextID("transfers")
+	transfer.CreatedAt = now()
+	data.transfers[transfer.ID] = transfer
+	return transfer, nil
+}
+
+func (q *memoryQueries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
+	data, done := q.begin()
+	defer done()
+
+	transfer, ok := data.transfers[id]
+	if !ok {
+		return Transfer{}, ErrRecordNotFound
+	}
+	return transfer, nil
+}
+
+func (q *memoryQueries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	return q.GetTransfer(ctx, id)
+}
+
+func (q *memoryQueries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
+	data, done := q.begin()
+	defer done()
+
+	transfers := rows(data.transfers, func(transfer Transfer) bool {
+		return transfer.FromAccountID == arg.FromAccountID || transfer.ToAccountID == arg.ToAccountID
+	})
+	return paginate(transfers, arg.Limit, arg.Offset), nil
+}
+
+func (q *memoryQueries) SumReversedAmount(ctx context.Context, reversedTransferID pgtype.Int8) (int64, error) {
+	data, done := q.begin()
+	defer done()
+
+	var total int64
+	for _, transfer := range data.transfers {
+		if transfer.ReversedTransferID.Valid && transfer.ReversedTransferID == reversedTransferID {
+			total += transfer.Amount
+		}
+	}
+	return total, nil
+}
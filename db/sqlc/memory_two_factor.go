@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+func (q *memoryQueries) UpsertTOTPCredential(ctx context.Context, arg UpsertTOTPCredentialParams) (TOTPCredential, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.users[arg.Username]; !ok {
+		return TOTPCredential{}, foreignKeyViolation("totp_credentials", "totp_credentials_username_fkey")
+	}
+
+	credential := TOTPCredential{
+		Username:  arg.Username,
+		Secret:    arg.Secret,
+		CreatedAt: now(),
+	}
+	data.totp[credential.Username] = credential
+	return credential, nil
+}
+
+func (q *memoryQueries) GetTOTPCredential(ctx context.Context, username string) (TOTPCredential, error) {
+	data, done := q.begin()
+	defer done()
+
+	credential, ok := data.totp[username]
+	if !ok {
+		return TOTPCredential{}, ErrRecordNotFound
+	}
+	return credential, nil
+}
+
+func (q *memoryQueries) ConfirmTOTPCredential(ctx context.Context, arg ConfirmTOTPCredentialParams) (TOTPCredential, error) {
+	data, done := q.begin()
+	defer done()
+
+	credential, ok := data.totp[arg.Username]
+	if !ok || credential.ConfirmedAt.Valid || credential.LastUsedStep >= arg.Step {
+		return TOTPCredential{}, ErrRecordNotFound
+	}
+	credential.ConfirmedAt = now()
+	credential.LastUsedStep = arg.Step
+	data.totp[credential.Username] = credential
+	return credential, nil
+}
+
+func (q *memoryQueries) UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (TOTPCredential, error) {
+	data, done := q.begin()
+	defer done()
+
+	credential, ok := data.totp[arg.Username]
+	if !ok || credential.LastUsedStep >= arg.Step {
+		return TOTPCredential{}, ErrRecordNotFound
+	}
+	credential.LastUsedStep = arg.Step
+	data.totp[credential.Username] = credential
+	return credential, nil
+}
+
+func (q *memoryQueries) DeleteTOTPCredential(ctx context.Context, username string) error {
+	data, done := q.begin()
+	defer done()
+
+	delete(data.totp, username)
+	return nil
+}
+
+func (q *memoryQueries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) (RecoveryCode, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.users[arg.Username]; !ok {
+		return RecoveryCode{}, foreignKeyViolation("recovery_codes", "recovery_codes_username_fkey")
+	}
+
+	recoveryCode := RecoveryCode{
+		ID:        data.nextID("recovery_codes"),
+		Username:  arg.Username,
+		CodeHash:  arg.CodeHash,
+		CreatedAt: now(),
+	}
+	data.recoveryCodes[recoveryCode.ID] = recoveryCode
+	return recoveryCode, nil
+}
+
+func (q *memoryQueries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (RecoveryCode, error) {
+	data, done := q.begin()
+	defer done()
+
+	matches := rows(data.recoveryCodes, func(recoveryCode RecoveryCode) bool {
+		return recoveryCode.Username == arg.Username && recoveryCode.CodeHash == arg.CodeHash && !recoveryCode.UsedAt.Valid
+	})
+	if len(matches) == 0 {
+		return RecoveryCode{}, ErrRecordNotFound
+	}
+	recoveryCode := matches[0]
+	recoveryCode.UsedAt = now()
+	data.recoveryCodes[recoveryCode.ID] = recoveryCode
+	return recoveryCode, nil
+}
+
+func (q *memoryQueries) DeleteRecoveryCodes(ctx context.Context, username string) error {
+	data, done := q.begin()
+	defer done()
+
+	for id, recoveryCode := range data.recoveryCodes {
+		if recoveryCode.Username == username {
+			delete(data.recoveryCodes, id)
+		}
+	}
+	return nil
+}
+
+func (q *memoryQueries) CreateLoginChallenge(ctx context.Context, arg CreateLoginChallengeParams) (LoginChallenge, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.users[arg.Username]; !ok {
+		return LoginChallenge{}, foreignKeyViolation("login_challenges", "login_challenges_username_fkey")
+	}
+	if _, ok := data.challenges[arg.ID]; ok {
+		return LoginChallenge{}, uniqueViolation("login_challenges", "login_challenges_pkey")
+	}
+
+	challenge := LoginChallenge{
+		ID:        arg.ID,
+		Username:  arg.Username,
+		ExpiresAt: arg.ExpiresAt,
+		CreatedAt: now(),
+	}
+	data.challenges[challenge.ID] = challenge
+	return challenge, nil
+}
+
+func (q *memoryQueries) AddLoginChallengeAttempt(ctx context.Context, id uuid.UUID) (LoginChallenge, error) {
+	data, done := q.begin()
+	defer done()
+
+	challenge, ok := data.challenges[id]
+	if !ok {
+		return LoginChallenge{}, ErrRecordNotFound
+	}
+	challenge.Attempts++
+	data.challenges[challenge.ID] = challenge
+	return challenge, nil
+}
+
+func (q *memoryQueries) DeleteLoginChallenge(ctx context.Context, id uuid.UUID) (int64, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.challenges[id]; !ok {
+		return 0, nil
+	}
+	delete(data.challenges, id)
+	return 1, nil
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (q *memoryQueries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.users[arg.Username]; ok {
+		return User{}, uniqueViolation("users", "users_pkey")
+	}
+	for _, user := range data.users {
+		if user.Email == arg.Email {
+			return User{}, uniqueViolation("users", "users_email_key")
+		}
+	}
+
+	user := User{
+		Username:          arg.Username,
+		Password:          arg.Password,
+		Fullname:          arg.Fullname,
+		Email:             arg.Email,
+		PasswordChangedAt: pgtype.Timestamptz{Time: time.Time{}.UTC(), Valid: true},
+		CreatedAt:         now(),
+		Role:              utils.DepositorRole,
+		Locale:            arg.Locale,
+	}
+	data.users[user.Username] = user
+	return user, nil
+}
+
+func (q *memoryQueries) GetUser(ctx context.Context, username string) (User, error) {
+	data, done := q.begin()
+	defer done()
+
+	user, ok := data.users[username]
+	if !ok {
+		return User{}, ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (q *memoryQueries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	data, done := q.begin()
+	defer done()
+
+	for _, user := range data.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return User{}, ErrRecordNotFound
+}
+
+func (q *memoryQueries) GetUserByPendingEmail(ctx context.Context, pendingEmail pgtype.Text) (User, error) {
+	data, done := q.begin()
+	defer done()
+
+	for _, user := range data.users {
+		if user.PendingEmail.Valid && user.PendingEmail == pendingEmail && user.PendingEmailExpiresAt.Time.After(time.Now()) {
+			return user, nil
+		}
+	}
+	return User{}, ErrRecordNotFound
+}
+
+func (q *memoryQueries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	data, done := q.begin()
+	defer done()
+
+	user, ok := data.users[arg.Username]
+	if !ok {
+		return User{}, ErrRecordNotFound
+	}
+	if arg.Email.Valid && arg.Email.String != user.Email {
+		for _, other := range data.users {
+			if other.Email == arg.Email.String {
+				return User{}, uniqueViolation("users", "users_email_key")
+			}
+		}
+		user.Email = arg.Email.String
+	}
+	if arg.PendingEmail.Valid && arg.PendingEmail != user.PendingEmail {
+		for _, other := range data.users {
+			if other.PendingEmail == arg.PendingEmail {
+				return User{}, uniqueViolation("users", "users_pending_email_key")
+			}
+		}
+		user.PendingEmail = arg.PendingEmail
+	}
+	if arg.PendingEmailExpiresAt.Valid {
+		user.PendingEmailExpiresAt = arg.PendingEmailExpiresAt
+	}
+	if arg.Password.Valid {
+		user.Password = arg.Password.String
+	}
+	if arg.PasswordChangedAt.Valid {
+		user.PasswordChangedAt = arg.PasswordChangedAt
+	}
+	if arg.Fullname.Valid {
+		user.Fullname = arg.Fullname.String
+	}
+	if arg.IsEmailVerified.Valid {
+		user.IsEmailVerified = arg.IsEmailVerified.Bool
+	}
+	if arg.Role.Valid {
+		if !utils.IsSupportedRole(arg.Role.String) {
+			return User{}, checkViolation("users", "role_valid")
+		}
+		user.Role = arg.Role.String
+	}
+	if arg.Locale.Valid {
+		user.Locale = arg.Locale.String
+	}
+	data.users[user.Username] = user
+	return user, nil
+}
+
+func (q *memoryQueries) CancelUserEmailChange(ctx context.Context, username string) (User, error) {
+	data, done := q.begin()
+	defer done()
+
+	user, ok := data.users[username]
+	if !ok {
+		return User{}, ErrRecordNotFound
+	}
+	user.PendingEmail = pgtype.Text{}
+	user.PendingEmailExpiresAt = pgtype.Timestamptz{}
+	data.users[user.Username] = user
+	return user, nil
+}
+
+func (q *memoryQueries) ReleaseExpiredPendingEmail(ctx context.Context, pendingEmail pgtype.Text) error {
+	data, done := q.begin()
+	defer done()
+
+	for _, user := range data.users {
+		if user.PendingEmail.Valid && user.PendingEmail == pendingEmail && !user.PendingEmailExpiresAt.Time.After(time.Now()) {
+			user.PendingEmail = pgtype.Text{}
+			user.PendingEmailExpiresAt = pgtype.Timestamptz{}
+			data.users[user.Username] = user
+		}
+	}
+	return nil
+}
+
+func (q *memoryQueries) ConfirmUserEmail(ctx context.Context, arg ConfirmUserEmailParams) (User, error) {
+	data, done := q.begin()
+	defer done()
+
+	user, ok := data.users[arg.Username]
+	if !ok || !user.PendingEmail.Valid || user.PendingEmail != arg.PendingEmail || !user.PendingEmailExpiresAt.Time.After(time.Now()) {
+		return User{}, ErrRecordNotFound
+	}
+	for _, other := range data.users {
+		if other.Email == user.PendingEmail.String && other.Username != user.Username {
+			return User{}, uniqueViolation("users", "users_email_key")
+		}
+	}
+	user.Email = user.PendingEmail.String
+	user.PendingEmail = pgtype.Text{}
+	user.PendingEmailExpiresAt = pgtype.Timestamptz{}
+	user.IsEmailVerified = true
+	data.users[user.Username] = user
+	return user, nil
+}
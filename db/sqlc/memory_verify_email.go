@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (q *memoryQueries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.users[arg.Username]; !ok {
+		return VerifyEmail{}, foreignKeyViolation("verify_emails", "verify_emails_username_fkey")
+	}
+
+	createdAt := now()
+	verifyEmail := VerifyEmail{
+		ID:         data.nextID("verify_emails"),
+		Username:   arg.Username,
+		Email:      arg.Email,
+		SecretCode: arg.SecretCode,
+		CreatedAt:  createdAt,
+		ExpiredAt:  pgtype.Timestamptz{Time: createdAt.Time.Add(15 * time.Minute), Valid: true},
+	}
+	data.verifyEmails[verifyEmail.ID] = verifyEmail
+	return verifyEmail, nil
+}
+
+func (q *memoryQueries) UpdateVerifyEmail(ctx context.Context, arg UpdateVerifyEmailParams) (VerifyEmail, error) {
+	data, done := q.begin()
+	defer done()
+
+	verifyEmail, ok := data.verifyEmails[arg.ID]
+	if !ok || verifyEmail.SecretCode != arg.SecretCode || verifyEmail.IsUsed || !verifyEmail.ExpiredAt.Time.After(time.Now()) {
+		return VerifyEmail{}, ErrRecordNotFound
+	}
+	verifyEmail.IsUsed = true
+	data.verifyEmails[verifyEmail.ID] = verifyEmail
+	return verifyEmail, nil
+}
+
+func (q *memoryQueries) InvalidateVerifyEmails(ctx context.Context, arg InvalidateVerifyEmailsParams) error {
+	data, done := q.begin()
+	defer done()
+
+	for id, verifyEmail := range data.verifyEmails {
+		if verifyEmail.Username == arg.Username && verifyEmail.Email == arg.Email && !verifyEmail.IsUsed {
+			verifyEmail.IsUsed = true
+			data.verifyEmails[id] = verifyEmail
+		}
+	}
+	return nil
+}
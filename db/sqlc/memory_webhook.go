@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"slices"
+)
+
+func (q *memoryQueries) CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.users[arg.Owner]; !ok {
+		return WebhookEndpoint{}, foreignKeyViolation("webhook_endpoints", "webhook_endpoints_owner_fkey")
+	}
+
+	endpoint := WebhookEndpoint{
+		ID:         data.nextID("webhook_endpoints"),
+		Owner:      arg.Owner,
+		Url:        arg.Url,
+		Secret:     arg.Secret,
+		EventTypes: slices.Clone(arg.EventTypes),
+		CreatedAt:  now(),
+	}
+	data.webhookEndpoints[endpoint.ID] = endpoint
+	return endpoint, nil
+}
+
+func (q *memoryQueries) GetWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoint, error) {
+	data, done := q.begin()
+	defer done()
+
+	endpoint, ok := data.webhookEndpoints[id]
+	if !ok {
+		return WebhookEndpoint{}, ErrRecordNotFound
+	}
+	return endpoint, nil
+}
+
+func (q *memoryQueries) ListWebhookEndpoints(ctx context.Context, owner string) ([]WebhookEndpoint, error) {
+	data, done := q.begin()
+	defer done()
+
+	return rows(data.webhookEndpoints, func(endpoint WebhookEndpoint) bool {
+		return endpoint.Owner == owner
+	}), nil
+}
+
+func (q *memoryQueries) ListWebhookEndpointsForEvent(ctx context.Context, arg ListWebhookEndpointsForEventParams) ([]WebhookEndpoint, error) {
+	data, done := q.begin()
+	defer done()
+
+	return rows(data.webhookEndpoints, func(endpoint WebhookEndpoint) bool {
+		return slices.Contains(arg.Owners, endpoint.Owner) && slices.Contains(endpoint.EventTypes, arg.EventType)
+	}), nil
+}
+
+func (q *memoryQueries) DeleteWebhookEndpoint(ctx context.Context, id int64) error {
+	data, done := q.begin()
+	defer done()
+
+	// the deliveries are deleted on cascade
+	for deliveryID, delivery := range data.webhookDeliveries {
+		if delivery.EndpointID == id {
+			delete(data.webhookDeliveries, deliveryID)
+		}
+	}
+	delete(data.webhookEndpoints, id)
+	return nil
+}
+
+func (q *memoryQueries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error) {
+	data, done := q.begin()
+	defer done()
+
+	if _, ok := data.webhookEndpoints[arg.EndpointID]; !ok {
+		return WebhookDelivery{}, foreignKeyViolation("webhook_deliveries", "webhook_deliveries_endpoint_id_fkey")
+	}
+
+	delivery := WebhookDelivery{
+		ID:             data.nextID("webhook_deliveries"),
+		EndpointID:     arg.EndpointID,
+		EventID:        arg.EventID,
+		EventType:      arg.EventType,
+		Attempt:        arg.Attempt,
+		Payload:        slices.Clone(arg.Payload),
+		ResponseStatus: arg.ResponseStatus,
+		Error:          arg.Error,
+		Succeeded:      arg.Succeeded,
+		DurationMs:     arg.DurationMs,
+		CreatedAt:      now(),
+	}
+	data.webhookDeliveries[delivery.ID] = delivery
+	return delivery, nil
+}
+
+func (q *memoryQueries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	data, done := q.begin()
+	defer done()
+
+	deliveries := rows(data.webhookDeliveries, func(delivery WebhookDelivery) bool {
+		return delivery.EndpointID == arg.EndpointID
+	})
+	slices.Reverse(deliveries)
+	return paginate(deliveries, arg.Limit, arg.Offset), nil
+}
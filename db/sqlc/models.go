@@ -10,11 +10,13 @@ import (
 )
 
 type Account struct {
-	ID        int64              `json:"id"`
-	Owner     string             `json:"owner"`
-	Balance   int64              `json:"balance"`
-	Currency  string             `json:"currency"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID             int64              `json:"id"`
+	Owner          string             `json:"owner"`
+	Balance        int64              `json:"balance"`
+	Currency       string             `json:"currency"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	OverdraftLimit int64              `json:"overdraft_limit"`
+	Kind           string             `json:"kind"`
 }
 
 type Entry struct {
@@ -24,6 +26,107 @@ type Entry struct {
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
+type FailedLogin struct {
+	ID        int64              `json:"id"`
+	Username  string             `json:"username"`
+	ClientIp  string             `json:"client_ip"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type FxRate struct {
+	ID           int64              `json:"id"`
+	FromCurrency string             `json:"from_currency"`
+	ToCurrency   string             `json:"to_currency"`
+	Rate         pgtype.Numeric     `json:"rate"`
+	EffectiveAt  pgtype.Timestamptz `json:"effective_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	Username    string             `json:"username"`
+	Key         string             `json:"key"`
+	RequestHash string             `json:"request_hash"`
+	Response    []byte             `json:"response"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type LoginChallenge struct {
+	ID        uuid.UUID          `json:"id"`
+	Username  string             `json:"username"`
+	Attempts  int32              `json:"attempts"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	Username              string             `json:"username"`
+	Channel               string             `json:"channel"`
+	TransferNotifications bool               `json:"transfer_notifications"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+}
+
+type OutboxMessage struct {
+	ID        int64              `json:"id"`
+	TaskType  string             `json:"task_type"`
+	Payload   []byte             `json:"payload"`
+	Queue     string             `json:"queue"`
+	MaxRetry  int32              `json:"max_retry"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	SentAt    pgtype.Timestamptz `json:"sent_at"`
+}
+
+type PasswordReset struct {
+	ID         int64              `json:"id"`
+	Username   string             `json:"username"`
+	Email      string             `json:"email"`
+	SecretCode string             `json:"secret_code"`
+	IsUsed     bool               `json:"is_used"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	ExpiredAt  pgtype.Timestamptz `json:"expired_at"`
+}
+
+type PasswordResetRequest struct {
+	ID        int64              `json:"id"`
+	Email     string             `json:"email"`
+	ClientIp  string             `json:"client_ip"`
+	LinkSent  bool               `json:"link_sent"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type RecoveryCode struct {
+	ID        int64              `json:"id"`
+	Username  string             `json:"username"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ScheduledTransfer struct {
+	ID             int64              `json:"id"`
+	Owner          string             `json:"owner"`
+	FromAccountID  int64              `json:"from_account_id"`
+	ToAccountID    int64              `json:"to_account_id"`
+	Amount         int64              `json:"amount"`
+	Frequency      string             `json:"frequency"`
+	CronExpression string             `json:"cron_expression"`
+	Status         string             `json:"status"`
+	StartAt        pgtype.Timestamptz `json:"start_at"`
+	NextRunAt      pgtype.Timestamptz `json:"next_run_at"`
+	LastRunAt      pgtype.Timestamptz `json:"last_run_at"`
+	FailureCount   int32              `json:"failure_count"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64              `json:"id"`
+	ScheduledTransferID int64              `json:"scheduled_transfer_id"`
+	TransferID          pgtype.Int8        `json:"transfer_id"`
+	Status              string             `json:"status"`
+	Error               string             `json:"error"`
+	ScheduledAt         pgtype.Timestamptz `json:"scheduled_at"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+}
+
 type Session struct {
 	ID           uuid.UUID          `json:"id"`
 	Username     string             `json:"username"`
@@ -35,22 +138,49 @@ type Session struct {
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 }
 
+type SigningKey struct {
+	Kid       string             `json:"kid"`
+	Algorithm string             `json:"algorithm"`
+	Key       []byte             `json:"key"`
+	RetiresAt pgtype.Timestamptz `json:"retires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	Encrypted bool               `json:"encrypted"`
+}
+
+type TOTPCredential struct {
+	Username     string             `json:"username"`
+	Secret       string             `json:"secret"`
+	ConfirmedAt  pgtype.Timestamptz `json:"confirmed_at"`
+	LastUsedStep int64              `json:"last_used_step"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
 type Transfer struct {
-	ID            int64              `json:"id"`
-	FromAccountID int64              `json:"from_account_id"`
-	ToAccountID   int64              `json:"to_account_id"`
-	Amount        int64              `json:"amount"`
-	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	ID                 int64              `json:"id"`
+	FromAccountID      int64              `json:"from_account_id"`
+	ToAccountID        int64              `json:"to_account_id"`
+	Amount             int64              `json:"amount"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	ExchangeRate       pgtype.Numeric     `json:"exchange_rate"`
+	FxRateID           pgtype.Int8        `json:"fx_rate_id"`
+	SourceTransferID   pgtype.Int8        `json:"source_transfer_id"`
+	ReversedTransferID pgtype.Int8        `json:"reversed_transfer_id"`
 }
 
 type User struct {
-	Username          string             `json:"username"`
-	Password          string             `json:"password"`
-	Fullname          string             `json:"fullname"`
-	Email             string             `json:"email"`
-	PasswordChangedAt pgtype.Timestamptz `json:"password_changed_at"`
-	CreatedAt         pgtype.Timestamptz `json:"created_at"`
-	IsEmailVerified   bool               `json:"is_email_verified"`
+	Username              string             `json:"username"`
+	Password              string             `json:"password"`
+	Fullname              string             `json:"fullname"`
+	Email                 string             `json:"email"`
+	PasswordChangedAt     pgtype.Timestamptz `json:"password_changed_at"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	IsEmailVerified       bool               `json:"is_email_verified"`
+	Role                  string             `json:"role"`
+	FailedLoginAttempts   int32              `json:"failed_login_attempts"`
+	LockedUntil           pgtype.Timestamptz `json:"locked_until"`
+	PendingEmail          pgtype.Text        `json:"pending_email"`
+	Locale                string             `json:"locale"`
+	PendingEmailExpiresAt pgtype.Timestamptz `json:"pending_email_expires_at"`
 }
 
 type VerifyEmail struct {
@@ -62,3 +192,26 @@ type VerifyEmail struct {
 	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 	ExpiredAt  pgtype.Timestamptz `json:"expired_at"`
 }
+
+type WebhookDelivery struct {
+	ID             int64              `json:"id"`
+	EndpointID     int64              `json:"endpoint_id"`
+	EventID        uuid.UUID          `json:"event_id"`
+	EventType      string             `json:"event_type"`
+	Attempt        int32              `json:"attempt"`
+	Payload        []byte             `json:"payload"`
+	ResponseStatus pgtype.Int4        `json:"response_status"`
+	Error          string             `json:"error"`
+	Succeeded      bool               `json:"succeeded"`
+	DurationMs     int64              `json:"duration_ms"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type WebhookEndpoint struct {
+	ID         int64              `json:"id"`
+	Owner      string             `json:"owner"`
+	Url        string             `json:"url"`
+	Secret     string             `json:"secret"`
+	EventTypes []string           `json:"event_types"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
@@ -0,0 +1,96 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: notification_preference.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getNotificationPreference = `-- name: GetNotificationPreference :one
+SELECT username, channel, transfer_notifications, updated_at FROM notification_preferences
+WHERE username = $1 AND channel = $2 LIMIT 1
+`
+
+type GetNotificationPreferenceParams struct {
+	Username string `json:"username"`
+	Channel  string `json:"channel"`
+}
+
+func (q *Queries) GetNotificationPreference(ctx context.Context, arg GetNotificationPreferenceParams) (NotificationPreference, error) {
+	row := q.db.QueryRow(ctx, getNotificationPreference, arg.Username, arg.Channel)
+	var i NotificationPreference
+	err := row.Scan(
+		&i.Username,
+		&i.Channel,
+		&i.TransferNotifications,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listNotificationPreferences = `-- name: ListNotificationPreferences :many
+SELECT username, channel, transfer_notifications, updated_at FROM notification_preferences
+WHERE username = $1
+ORDER BY channel
+`
+
+func (q *Queries) ListNotificationPreferences(ctx context.Context, username string) ([]NotificationPreference, error) {
+	rows, err := q.db.Query(ctx, listNotificationPreferences, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []NotificationPreference{}
+	for rows.Next() {
+		var i NotificationPreference
+		if err := rows.Scan(
+			&i.Username,
+			&i.Channel,
+			&i.TransferNotifications,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertNotificationPreference = `-- name: UpsertNotificationPreference :one
+INSERT INTO notification_preferences (
+  username,
+  channel,
+  transfer_notifications
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (username, channel) DO UPDATE
+SET
+  transfer_notifications = EXCLUDED.transfer_notifications,
+  updated_at = now()
+RETURNING username, channel, transfer_notifications, updated_at
+`
+
+type UpsertNotificationPreferenceParams struct {
+	Username              string `json:"username"`
+	Channel               string `json:"channel"`
+	TransferNotifications bool   `json:"transfer_notifications"`
+}
+
+func (q *Queries) UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) (NotificationPreference, error) {
+	row := q.db.QueryRow(ctx, upsertNotificationPreference, arg.Username, arg.Channel, arg.TransferNotifications)
+	var i NotificationPreference
+	err := row.Scan(
+		&i.Username,
+		&i.Channel,
+		&i.TransferNotifications,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,109 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: outbox.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createOutboxMessage = `-- name: CreateOutboxMessage :one
+INSERT INTO outbox (
+    task_type,
+    payload,
+    queue,
+    max_retry
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING id, task_type, payload, queue, max_retry, created_at, sent_at
+`
+
+type CreateOutboxMessageParams struct {
+	TaskType string `json:"task_type"`
+	Payload  []byte `json:"payload"`
+	Queue    string `json:"queue"`
+	MaxRetry int32  `json:"max_retry"`
+}
+
+func (q *Queries) CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) (OutboxMessage, error) {
+	row := q.db.QueryRow(ctx, createOutboxMessage,
+		arg.TaskType,
+		arg.Payload,
+		arg.Queue,
+		arg.MaxRetry,
+	)
+	var i OutboxMessage
+	err := row.Scan(
+		&i.ID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.MaxRetry,
+		&i.CreatedAt,
+		&i.SentAt,
+	)
+	return i, err
+}
+
+const listUnsentOutboxMessages = `-- name: ListUnsentOutboxMessages :many
+SELECT id, task_type, payload, queue, max_retry, created_at, sent_at FROM outbox
+WHERE sent_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) ListUnsentOutboxMessages(ctx context.Context, limit int32) ([]OutboxMessage, error) {
+	rows, err := q.db.Query(ctx, listUnsentOutboxMessages, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OutboxMessage{}
+	for rows.Next() {
+		var i OutboxMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskType,
+			&i.Payload,
+			&i.Queue,
+			&i.MaxRetry,
+			&i.CreatedAt,
+			&i.SentAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxMessageSent = `-- name: MarkOutboxMessageSent :one
+UPDATE outbox
+SET
+    sent_at = now()
+WHERE
+    id = $1
+    AND sent_at IS NULL
+RETURNING id, task_type, payload, queue, max_retry, created_at, sent_at
+`
+
+func (q *Queries) MarkOutboxMessageSent(ctx context.Context, id int64) (OutboxMessage, error) {
+	row := q.db.QueryRow(ctx, markOutboxMessageSent, id)
+	var i OutboxMessage
+	err := row.Scan(
+		&i.ID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.MaxRetry,
+		&i.CreatedAt,
+		&i.SentAt,
+	)
+	return i, err
+}
@@ -0,0 +1,93 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: password_reset_request.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countPasswordResetRequestsByClientIp = `-- name: CountPasswordResetRequestsByClientIp :one
+SELECT count(*) FROM password_reset_requests
+WHERE
+    client_ip = $1
+    AND created_at > $2
+`
+
+type CountPasswordResetRequestsByClientIpParams struct {
+	ClientIp string             `json:"client_ip"`
+	Since    pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) CountPasswordResetRequestsByClientIp(ctx context.Context, arg CountPasswordResetRequestsByClientIpParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPasswordResetRequestsByClientIp, arg.ClientIp, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSentPasswordResetRequests = `-- name: CountSentPasswordResetRequests :one
+SELECT count(*) FROM password_reset_requests
+WHERE
+    email = $1
+    AND link_sent = TRUE
+    AND created_at > $2
+`
+
+type CountSentPasswordResetRequestsParams struct {
+	Email string             `json:"email"`
+	Since pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) CountSentPasswordResetRequests(ctx context.Context, arg CountSentPasswordResetRequestsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSentPasswordResetRequests, arg.Email, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createPasswordResetRequest = `-- name: CreatePasswordResetRequest :one
+INSERT INTO password_reset_requests (
+    email,
+    client_ip,
+    link_sent
+) VALUES (
+    $1, $2, $3
+) RETURNING id, email, client_ip, link_sent, created_at
+`
+
+type CreatePasswordResetRequestParams struct {
+	Email    string `json:"email"`
+	ClientIp string `json:"client_ip"`
+	LinkSent bool   `json:"link_sent"`
+}
+
+func (q *Queries) CreatePasswordResetRequest(ctx context.Context, arg CreatePasswordResetRequestParams) (PasswordResetRequest, error) {
+	row := q.db.QueryRow(ctx, createPasswordResetRequest, arg.Email, arg.ClientIp, arg.LinkSent)
+	var i PasswordResetRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.ClientIp,
+		&i.LinkSent,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deletePasswordResetRequestsBefore = `-- name: DeletePasswordResetRequestsBefore :execrows
+DELETE FROM password_reset_requests
+WHERE created_at < $1
+`
+
+func (q *Queries) DeletePasswordResetRequestsBefore(ctx context.Context, before pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePasswordResetRequestsBefore, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,157 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: signing_key.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createSigningKey = `-- name: CreateSigningKey :one
+INSERT INTO signing_keys (
+  kid,
+  algorithm,
+  key,
+  encrypted
+) VALUES (
+  $1, $2, $3, true
+)
+RETURNING kid, algorithm, key, retires_at, created_at, encrypted
+`
+
+type CreateSigningKeyParams struct {
+	Kid       string `json:"kid"`
+	Algorithm string `json:"algorithm"`
+	Key       []byte `json:"key"`
+}
+
+func (q *Queries) CreateSigningKey(ctx context.Context, arg CreateSigningKeyParams) (SigningKey, error) {
+	row := q.db.QueryRow(ctx, createSigningKey, arg.Kid, arg.Algorithm, arg.Key)
+	var i SigningKey
+	err := row.Scan(
+		&i.Kid,
+		&i.Algorithm,
+		&i.Key,
+		&i.RetiresAt,
+		&i.CreatedAt,
+		&i.Encrypted,
+	)
+	return i, err
+}
+
+const encryptSigningKey = `-- name: EncryptSigningKey :one
+UPDATE signing_keys
+SET
+  key = $1,
+  encrypted = true
+WHERE
+  kid = $2
+  AND NOT encrypted
+RETURNING kid, algorithm, key, retires_at, created_at, encrypted
+`
+
+type EncryptSigningKeyParams struct {
+	Key []byte `json:"key"`
+	Kid string `json:"kid"`
+}
+
+func (q *Queries) EncryptSigningKey(ctx context.Context, arg EncryptSigningKeyParams) (SigningKey, error) {
+	row := q.db.QueryRow(ctx, encryptSigningKey, arg.Key, arg.Kid)
+	var i SigningKey
+	err := row.Scan(
+		&i.Kid,
+		&i.Algorithm,
+		&i.Key,
+		&i.RetiresAt,
+		&i.CreatedAt,
+		&i.Encrypted,
+	)
+	return i, err
+}
+
+const listSigningKeys = `-- name: ListSigningKeys :many
+SELECT kid, algorithm, key, retires_at, created_at, encrypted FROM signing_keys
+WHERE
+  algorithm = $1
+  AND (retires_at IS NULL OR retires_at > $2)
+ORDER BY created_at DESC, kid
+`
+
+type ListSigningKeysParams struct {
+	Algorithm string             `json:"algorithm"`
+	Now       pgtype.Timestamptz `json:"now"`
+}
+
+func (q *Queries) ListSigningKeys(ctx context.Context, arg ListSigningKeysParams) ([]SigningKey, error) {
+	rows, err := q.db.Query(ctx, listSigningKeys, arg.Algorithm, arg.Now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SigningKey{}
+	for rows.Next() {
+		var i SigningKey
+		if err := rows.Scan(
+			&i.Kid,
+			&i.Algorithm,
+			&i.Key,
+			&i.RetiresAt,
+			&i.CreatedAt,
+			&i.Encrypted,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const retireSigningKeys = `-- name: RetireSigningKeys :many
+UPDATE signing_keys
+SET retires_at = $1
+WHERE
+  algorithm = $2
+  AND kid <> $3
+  AND (retires_at IS NULL OR retires_at > $1)
+RETURNING kid, algorithm, key, retires_at, created_at, encrypted
+`
+
+type RetireSigningKeysParams struct {
+	RetiresAt pgtype.Timestamptz `json:"retires_at"`
+	Algorithm string             `json:"algorithm"`
+	ExceptKid string             `json:"except_kid"`
+}
+
+func (q *Queries) RetireSigningKeys(ctx context.Context, arg RetireSigningKeysParams) ([]SigningKey, error) {
+	rows, err := q.db.Query(ctx, retireSigningKeys, arg.RetiresAt, arg.Algorithm, arg.ExceptKid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SigningKey{}
+	for rows.Next() {
+		var i SigningKey
+		if err := rows.Scan(
+			&i.Kid,
+			&i.Algorithm,
+			&i.Key,
+			&i.RetiresAt,
+			&i.CreatedAt,
+			&i.Encrypted,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const signingKeysLoadTimeout = 5 * time.Second
+
+// NewSigningKeyring creates the keyring holding the signing keys of the store, which keyCipher decrypts.
+// The key set in config is added to the store when there is no key for its algorithm yet.
+func NewSigningKeyring(config utils.Config, store Store, keyCipher *utils.SigningKeyCipher) (*utils.Keyring, error) {
+	configKey, err := utils.ConfigSigningKey(config)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewKeyring(configKey.Algorithm, configKey.Kid, func() ([]utils.SigningKey, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), signingKeysLoadTimeout)
+		defer cancel()
+		return loadSigningKeys(ctx, store, keyCipher, configKey)
+	})
+}
+
+func loadSigningKeys(ctx context.Context, store Store, keyCipher *utils.SigningKeyCipher, configKey utils.SigningKey) ([]utils.SigningKey, error) {
+	args := ListSigningKeysParams{
+		Algorithm: configKey.Algorithm,
+		Now:       pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	signingKeys, err := store.ListSigningKeys(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(signingKeys) == 0 {
+		sealedKey, err := keyCipher.Seal(configKey.Kid, configKey.Key)
+		if err != nil {
+			return nil, err
+		}
+		_, err = store.CreateSigningKey(ctx, CreateSigningKeyParams{
+			Kid:       configKey.Kid,
+			Algorithm: configKey.Algorithm,
+			Key:       sealedKey,
+		})
+		// another server may have added it first
+		if err != nil && ErrorCode(err) != UniqueViolation {
+			return nil, err
+		}
+		signingKeys, err = store.ListSigningKeys(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]utils.SigningKey, 0, len(signingKeys))
+	for _, signingKey := range signingKeys {
+		key, err := openSigningKey(ctx, store, keyCipher, signingKey)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, utils.SigningKey{
+			Kid:       signingKey.Kid,
+			Algorithm: signingKey.Algorithm,
+			Key:       key,
+			RetiresAt: signingKey.RetiresAt.Time,
+		})
+	}
+	return keys, nil
+}
+
+// openSigningKey decrypts a stored signing key. A key stored before keys were encrypted
+// is encrypted in place, so that it doesn't stay readable in the database.
+func openSigningKey(ctx context.Context, store Store, keyCipher *utils.SigningKeyCipher, signingKey SigningKey) ([]byte, error) {
+	if signingKey.Encrypted {
+		key, err := keyCipher.Open(signingKey.Kid, signingKey.Key)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", signingKey.Kid, err)
+		}
+		return key, nil
+	}
+
+	sealedKey, err := keyCipher.Seal(signingKey.Kid, signingKey.Key)
+	if err != nil {
+		return nil, err
+	}
+	_, err = store.EncryptSigningKey(ctx, EncryptSigningKeyParams{
+		Kid: signingKey.Kid,
+		Key: sealedKey,
+	})
+	// another server may have encrypted it first
+	if err != nil && !errors.Is(err, ErrRecordNotFound) {
+		return nil, err
+	}
+	return signingKey.Key, nil
+}
@@ -1,19 +1,52 @@
 package db
 
 import (
+	"context"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// store provides all functions to execute db queries and transactions
-type Store struct {
+// Store provides all functions to execute db queries and transactions
+type Store interface {
+	Querier
+	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (CreateAccountTxResult, error)
+	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	DepositTx(ctx context.Context, arg CashTxParams) (CashTxResult, error)
+	WithdrawTx(ctx context.Context, arg CashTxParams) (CashTxResult, error)
+	StatementTx(ctx context.Context, arg StatementTxParams) (StatementTxResult, error)
+	RunScheduledTransferTx(ctx context.Context, arg RunScheduledTransferTxParams) (RunScheduledTransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error)
+	RotateSigningKeyTx(ctx context.Context, arg RotateSigningKeyTxParams) (RotateSigningKeyTxResult, error)
+	EnrollTOTPTx(ctx context.Context, arg EnrollTOTPTxParams) (EnrollTOTPTxResult, error)
+	DisableTOTPTx(ctx context.Context, username string) error
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error)
+	RecordFailedLoginTx(ctx context.Context, arg RecordFailedLoginTxParams) (RecordFailedLoginTxResult, error)
+	RelayOutboxTx(ctx context.Context, arg RelayOutboxTxParams) (RelayOutboxTxResult, error)
+}
+
+// txStore implements the transactions of Store on top of an execTx function,
+// so that every Store implementation shares the same transaction logic
+type txStore struct {
+	execTx         func(ctx context.Context, fn func(q Querier) error) error
+	execReadOnlyTx func(ctx context.Context, fn func(q Querier) error) error
+}
+
+// SQLStore provides all functions to execute SQL queries and transactions on postgres
+type SQLStore struct {
 	*Queries
+	txStore
 	db *pgxpool.Pool
 }
 
 // NewStore creates a new store
-func NewStore(db *pgxpool.Pool) *Store {
-	return &Store{
+func NewStore(db *pgxpool.Pool) Store {
+	store := &SQLStore{
 		db:      db,
 		Queries: New(db),
 	}
-}
\ No newline at end of file
+	store.txStore = txStore{execTx: store.execTx, execReadOnlyTx: store.execReadOnlyTx}
+	return store
+}
@@ -7,8 +7,96 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const createFxTransfer = `-- name: CreateFxTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  exchange_rate,
+  fx_rate_id,
+  source_transfer_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, from_account_id, to_account_id, amount, created_at, exchange_rate, fx_rate_id, source_transfer_id, reversed_transfer_id
+`
+
+type CreateFxTransferParams struct {
+	FromAccountID    int64          `json:"from_account_id"`
+	ToAccountID      int64          `json:"to_account_id"`
+	Amount           int64          `json:"amount"`
+	ExchangeRate     pgtype.Numeric `json:"exchange_rate"`
+	FxRateID         pgtype.Int8    `json:"fx_rate_id"`
+	SourceTransferID pgtype.Int8    `json:"source_transfer_id"`
+}
+
+func (q *Queries) CreateFxTransfer(ctx context.Context, arg CreateFxTransferParams) (Transfer, error) {
+	row := q.db.QueryRow(ctx, createFxTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ExchangeRate,
+		arg.FxRateID,
+		arg.SourceTransferID,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ExchangeRate,
+		&i.FxRateID,
+		&i.SourceTransferID,
+		&i.ReversedTransferID,
+	)
+	return i, err
+}
+
+const createReversalTransfer = `-- name: CreateReversalTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  reversed_transfer_id
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, from_account_id, to_account_id, amount, created_at, exchange_rate, fx_rate_id, source_transfer_id, reversed_transfer_id
+`
+
+type CreateReversalTransferParams struct {
+	FromAccountID      int64       `json:"from_account_id"`
+	ToAccountID        int64       `json:"to_account_id"`
+	Amount             int64       `json:"amount"`
+	ReversedTransferID pgtype.Int8 `json:"reversed_transfer_id"`
+}
+
+func (q *Queries) CreateReversalTransfer(ctx context.Context, arg CreateReversalTransferParams) (Transfer, error) {
+	row := q.db.QueryRow(ctx, createReversalTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ReversedTransferID,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ExchangeRate,
+		&i.FxRateID,
+		&i.SourceTransferID,
+		&i.ReversedTransferID,
+	)
+	return i, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
   from_account_id,
@@ -16,7 +104,7 @@ INSERT INTO transfers (
   amount
 ) VALUES (
   $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+) RETURNING id, from_account_id, to_account_id, amount, created_at, exchange_rate, fx_rate_id, source_transfer_id, reversed_transfer_id
 `
 
 type CreateTransferParams struct {
@@ -34,12 +122,16 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ExchangeRate,
+		&i.FxRateID,
+		&i.SourceTransferID,
+		&i.ReversedTransferID,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, exchange_rate, fx_rate_id, source_transfer_id, reversed_transfer_id FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -52,12 +144,39 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ExchangeRate,
+		&i.FxRateID,
+		&i.SourceTransferID,
+		&i.ReversedTransferID,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, exchange_rate, fx_rate_id, source_transfer_id, reversed_transfer_id FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRow(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ExchangeRate,
+		&i.FxRateID,
+		&i.SourceTransferID,
+		&i.ReversedTransferID,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, exchange_rate, fx_rate_id, source_transfer_id, reversed_transfer_id FROM transfers
 WHERE 
     from_account_id = $1 OR
     to_account_id = $2
@@ -93,6 +212,10 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ExchangeRate,
+			&i.FxRateID,
+			&i.SourceTransferID,
+			&i.ReversedTransferID,
 		); err != nil {
 			return nil, err
 		}
@@ -103,3 +226,15 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	}
 	return items, nil
 }
+
+const sumReversedAmount = `-- name: SumReversedAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM transfers
+WHERE reversed_transfer_id = $1
+`
+
+func (q *Queries) SumReversedAmount(ctx context.Context, reversedTransferID pgtype.Int8) (int64, error) {
+	row := q.db.QueryRow(ctx, sumReversedAmount, reversedTransferID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
@@ -0,0 +1,261 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: two_factor.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addLoginChallengeAttempt = `-- name: AddLoginChallengeAttempt :one
+UPDATE login_challenges
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING id, username, attempts, expires_at, created_at
+`
+
+func (q *Queries) AddLoginChallengeAttempt(ctx context.Context, id uuid.UUID) (LoginChallenge, error) {
+	row := q.db.QueryRow(ctx, addLoginChallengeAttempt, id)
+	var i LoginChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const confirmTOTPCredential = `-- name: ConfirmTOTPCredential :one
+UPDATE totp_credentials
+SET
+  confirmed_at = now(),
+  last_used_step = $1
+WHERE
+  username = $2
+  AND confirmed_at IS NULL
+  AND last_used_step < $1
+RETURNING username, secret, confirmed_at, last_used_step, created_at
+`
+
+type ConfirmTOTPCredentialParams struct {
+	Step     int64  `json:"step"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) ConfirmTOTPCredential(ctx context.Context, arg ConfirmTOTPCredentialParams) (TOTPCredential, error) {
+	row := q.db.QueryRow(ctx, confirmTOTPCredential, arg.Step, arg.Username)
+	var i TOTPCredential
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createLoginChallenge = `-- name: CreateLoginChallenge :one
+INSERT INTO login_challenges (
+  id,
+  username,
+  expires_at
+) VALUES (
+  $1, $2, $3
+)
+RETURNING id, username, attempts, expires_at, created_at
+`
+
+type CreateLoginChallengeParams struct {
+	ID        uuid.UUID          `json:"id"`
+	Username  string             `json:"username"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateLoginChallenge(ctx context.Context, arg CreateLoginChallengeParams) (LoginChallenge, error) {
+	row := q.db.QueryRow(ctx, createLoginChallenge, arg.ID, arg.Username, arg.ExpiresAt)
+	var i LoginChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :one
+INSERT INTO recovery_codes (
+  username,
+  code_hash
+) VALUES (
+  $1, $2
+)
+RETURNING id, username, code_hash, used_at, created_at
+`
+
+type CreateRecoveryCodeParams struct {
+	Username string `json:"username"`
+	CodeHash string `json:"code_hash"`
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) (RecoveryCode, error) {
+	row := q.db.QueryRow(ctx, createRecoveryCode, arg.Username, arg.CodeHash)
+	var i RecoveryCode
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.CodeHash,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteLoginChallenge = `-- name: DeleteLoginChallenge :execrows
+DELETE FROM login_challenges
+WHERE id = $1
+`
+
+func (q *Queries) DeleteLoginChallenge(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteLoginChallenge, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE username = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, username string) error {
+	_, err := q.db.Exec(ctx, deleteRecoveryCodes, username)
+	return err
+}
+
+const deleteTOTPCredential = `-- name: DeleteTOTPCredential :exec
+DELETE FROM totp_credentials
+WHERE username = $1
+`
+
+func (q *Queries) DeleteTOTPCredential(ctx context.Context, username string) error {
+	_, err := q.db.Exec(ctx, deleteTOTPCredential, username)
+	return err
+}
+
+const getTOTPCredential = `-- name: GetTOTPCredential :one
+SELECT username, secret, confirmed_at, last_used_step, created_at FROM totp_credentials
+WHERE username = $1 LIMIT 1
+`
+
+func (q *Queries) GetTOTPCredential(ctx context.Context, username string) (TOTPCredential, error) {
+	row := q.db.QueryRow(ctx, getTOTPCredential, username)
+	var i TOTPCredential
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertTOTPCredential = `-- name: UpsertTOTPCredential :one
+INSERT INTO totp_credentials (
+  username,
+  secret
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (username) DO UPDATE
+SET
+  secret = EXCLUDED.secret,
+  confirmed_at = NULL,
+  last_used_step = 0,
+  created_at = now()
+RETURNING username, secret, confirmed_at, last_used_step, created_at
+`
+
+type UpsertTOTPCredentialParams struct {
+	Username string `json:"username"`
+	Secret   string `json:"secret"`
+}
+
+func (q *Queries) UpsertTOTPCredential(ctx context.Context, arg UpsertTOTPCredentialParams) (TOTPCredential, error) {
+	row := q.db.QueryRow(ctx, upsertTOTPCredential, arg.Username, arg.Secret)
+	var i TOTPCredential
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :one
+UPDATE recovery_codes
+SET used_at = now()
+WHERE
+  username = $1
+  AND code_hash = $2
+  AND used_at IS NULL
+RETURNING id, username, code_hash, used_at, created_at
+`
+
+type UseRecoveryCodeParams struct {
+	Username string `json:"username"`
+	CodeHash string `json:"code_hash"`
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (RecoveryCode, error) {
+	row := q.db.QueryRow(ctx, useRecoveryCode, arg.Username, arg.CodeHash)
+	var i RecoveryCode
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.CodeHash,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useTOTPStep = `-- name: UseTOTPStep :one
+UPDATE totp_credentials
+SET last_used_step = $1
+WHERE
+  username = $2
+  AND last_used_step < $1
+RETURNING username, secret, confirmed_at, last_used_step, created_at
+`
+
+type UseTOTPStepParams struct {
+	Step     int64  `json:"step"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (TOTPCredential, error) {
+	row := q.db.QueryRow(ctx, useTOTPStep, arg.Step, arg.Username)
+	var i TOTPCredential
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
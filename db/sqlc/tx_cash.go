@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrSystemAccount is returned when a customer operation targets an internal account of the bank
+var ErrSystemAccount = errors.New("cannot operate on a system account")
+
+// CashTxParams contains the input parameters of a deposit or a withdrawal
+type CashTxParams struct {
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+}
+
+// CashTxResult is the result of a deposit or a withdrawal. The transfer and
+// entries are booked against the cash account of the account's currency.
+type CashTxResult struct {
+	Transfer    Transfer `json:"transfer"`
+	Account     Account  `json:"account"`
+	CashAccount Account  `json:"cash_account"`
+	Entry       Entry    `json:"entry"`
+	CashEntry   Entry    `json:"cash_entry"`
+}
+
+// DepositTx brings money into an account from the cash account of its currency
+func (store txStore) DepositTx(ctx context.Context, arg CashTxParams) (CashTxResult, error) {
+	var result CashTxResult
+
+	err := store.execTx(ctx, func(q Querier) error {
+		cashAccount, err := getCashAccount(ctx, q, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		transferResult, err := transfer(ctx, q, TransferTxParams{
+			FromAccountId: cashAccount.ID,
+			ToAccountID:   arg.AccountID,
+			Amount:        arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result = CashTxResult{
+			Transfer:    transferResult.Transfer,
+			Account:     transferResult.ToAccount,
+			CashAccount: transferResult.FromAccount,
+			Entry:       transferResult.ToEntry,
+			CashEntry:   transferResult.FromEntry,
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// WithdrawTx takes money out of an account into the cash account of its currency
+func (store txStore) WithdrawTx(ctx context.Context, arg CashTxParams) (CashTxResult, error) {
+	var result CashTxResult
+
+	err := store.execTx(ctx, func(q Querier) error {
+		cashAccount, err := getCashAccount(ctx, q, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		transferResult, err := transfer(ctx, q, TransferTxParams{
+			FromAccountId: arg.AccountID,
+			ToAccountID:   cashAccount.ID,
+			Amount:        arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result = CashTxResult{
+			Transfer:    transferResult.Transfer,
+			Account:     transferResult.FromAccount,
+			CashAccount: transferResult.ToAccount,
+			Entry:       transferResult.FromEntry,
+			CashEntry:   transferResult.ToEntry,
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// getCashAccount returns the cash account matching the currency of a customer account
+func getCashAccount(ctx context.Context, q Querier, accountID int64) (Account, error) {
+	account, err := q.GetAccount(ctx, accountID)
+	if err != nil {
+		return Account{}, err
+	}
+	if account.IsSystem() {
+		return Account{}, ErrSystemAccount
+	}
+
+	cashAccount, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+		Kind:     AccountKindCash,
+		Currency: account.Currency,
+	})
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return Account{}, fmt.Errorf("no cash account for currency %s", account.Currency)
+		}
+		return Account{}, err
+	}
+	return cashAccount, nil
+}
@@ -0,0 +1,33 @@
+package db
+
+import "context"
+
+type CreateAccountTxParams struct {
+	CreateAccountParams
+	// AfterCreate is called with the created account, q runs its queries in the transaction
+	AfterCreate func(q Querier, account Account) error
+}
+
+type CreateAccountTxResult struct {
+	Account Account
+}
+
+func (store txStore) CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (CreateAccountTxResult, error) {
+	var result CreateAccountTxResult
+
+	err := store.execTx(ctx, func(q Querier) error {
+		var err error
+
+		result.Account, err = q.CreateAccount(ctx, arg.CreateAccountParams)
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterCreate != nil {
+			return arg.AfterCreate(q, result.Account)
+		}
+		return nil
+	})
+
+	return result, err
+}
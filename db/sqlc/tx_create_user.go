@@ -4,17 +4,18 @@ import "context"
 
 type CreateUserTxParams struct {
 	CreateUserParams
-	AfterCreate func(user User) error
+	// AfterCreate is called with the created user, q runs its queries in the transaction
+	AfterCreate func(q Querier, user User) error
 }
 
 type CreateUserTxResult struct {
 	User User
 }
 
-func (store *Store) CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error) {
+func (store txStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error) {
 	var result CreateUserTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
+	err := store.execTx(ctx, func(q Querier) error {
 		var err error
 
 		result.User, err = q.CreateUser(ctx, arg.CreateUserParams)
@@ -22,7 +23,7 @@ func (store *Store) CreateUserTx(ctx context.Context, arg CreateUserTxParams) (C
 			return err
 		}
 
-		return arg.AfterCreate(result.User)
+		return arg.AfterCreate(q, result.User)
 	})
 
 	return result, err
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type RecordFailedLoginTxParams struct {
+	CreateFailedLoginParams
+	// LockDuration returns how long the user is locked out after failedAttempts failed logins in a row, 0 for not at all
+	LockDuration func(failedAttempts int32) time.Duration
+	// AfterLock is called when the failed login locks the user out, q runs its queries in the transaction
+	AfterLock func(q Querier, user User) error
+}
+
+type RecordFailedLoginTxResult struct {
+	FailedLogin FailedLogin
+	User        User
+	IsLocked    bool
+}
+
+// RecordFailedLoginTx records a failed login, and locks the user out when it has failed too many times.
+// A failed login for a username that does not exist is only recorded.
+func (store txStore) RecordFailedLoginTx(ctx context.Context, arg RecordFailedLoginTxParams) (RecordFailedLoginTxResult, error) {
+	var result RecordFailedLoginTxResult
+
+	err := store.execTx(ctx, func(q Querier) error {
+		var err error
+
+		result.FailedLogin, err = q.CreateFailedLogin(ctx, arg.CreateFailedLoginParams)
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.AddUserFailedLoginAttempt(ctx, arg.Username)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		lockDuration := arg.LockDuration(result.User.FailedLoginAttempts)
+		if lockDuration <= 0 {
+			return nil
+		}
+		result.User, err = q.LockUser(ctx, LockUserParams{
+			Username: result.User.Username,
+			LockedUntil: pgtype.Timestamptz{
+				Time:  time.Now().Add(lockDuration),
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		result.IsLocked = true
+
+		if arg.AfterLock != nil {
+			return arg.AfterLock(q, result.User)
+		}
+		return nil
+	})
+
+	return result, err
+}
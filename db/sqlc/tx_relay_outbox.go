@@ -0,0 +1,47 @@
+package db
+
+import "context"
+
+type RelayOutboxTxParams struct {
+	// Limit is the most messages relayed at once
+	Limit int32
+	// Publish hands a message off to the task queue
+	Publish func(message OutboxMessage) error
+}
+
+type RelayOutboxTxResult struct {
+	Sent []OutboxMessage
+}
+
+// RelayOutboxTx publishes the unsent messages of the outbox in order and marks them sent.
+// Messages being relayed by another transaction are skipped. When a message fails to publish,
+// the messages before it stay marked sent and the error is returned.
+func (store txStore) RelayOutboxTx(ctx context.Context, arg RelayOutboxTxParams) (RelayOutboxTxResult, error) {
+	var result RelayOutboxTxResult
+	var publishErr error
+
+	err := store.execTx(ctx, func(q Querier) error {
+		messages, err := q.ListUnsentOutboxMessages(ctx, arg.Limit)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			if publishErr = arg.Publish(message); publishErr != nil {
+				return nil
+			}
+
+			message, err = q.MarkOutboxMessageSent(ctx, message.ID)
+			if err != nil {
+				return err
+			}
+			result.Sent = append(result.Sent, message)
+		}
+		return nil
+	})
+	if err != nil {
+		return RelayOutboxTxResult{}, err
+	}
+
+	return result, publishErr
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ResetPasswordTxParams struct {
+	ResetId    int64
+	SecretCode string
+	// HashedPassword is the new password of the user
+	HashedPassword string
+}
+
+type ResetPasswordTxResult struct {
+	User            User
+	BlockedSessions int64
+}
+
+// ResetPasswordTx sets the password of the user a reset link was sent to. The link is used up, together with
+// every other link of the user, all the sessions of the user are blocked and a pending email change is dropped.
+func (store txStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error) {
+	var result ResetPasswordTxResult
+
+	err := store.execTx(ctx, func(q Querier) error {
+		passwordReset, err := q.UsePasswordReset(ctx, UsePasswordResetParams{
+			ID:         arg.ResetId,
+			SecretCode: arg.SecretCode,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.UpdateUser(ctx, UpdateUserParams{
+			Username: passwordReset.Username,
+			Password: pgtype.Text{
+				String: arg.HashedPassword,
+				Valid:  true,
+			},
+			PasswordChangedAt: pgtype.Timestamptz{
+				Time:  time.Now(),
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.InvalidatePasswordResets(ctx, passwordReset.Username)
+		if err != nil {
+			return err
+		}
+
+		if result.User.PendingEmail.Valid {
+			result.User, err = cancelEmailChange(ctx, q, result.User)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.BlockedSessions, err = q.BlockUserSessions(ctx, BlockUserSessionsParams{
+			Username: passwordReset.Username,
+			ExceptID: uuid.Nil,
+		})
+		return err
+	})
+
+	return result, err
+}
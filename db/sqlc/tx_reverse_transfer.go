@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// ErrTransferNotReversible is returned for transfers that can't be refunded, like reversals themselves
+	ErrTransferNotReversible = errors.New("transfer cannot be reversed")
+	// ErrReversalExceedsAmount is returned when the reversals of a transfer would refund more than its amount
+	ErrReversalExceedsAmount = errors.New("reversal exceeds the amount left to refund")
+)
+
+// ReverseTransferTxParams contains the input parameters of a full or partial refund of a transfer
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	Amount     int64 `json:"amount"`
+	// AfterTransfer is called with the compensating transfer, q runs its queries in the transaction
+	AfterTransfer func(q Querier, result TransferTxResult) error `json:"-"`
+}
+
+// ReverseTransferTxResult is the result of a reversal. The embedded transfer goes from the
+// recipient of the original transfer back to its sender.
+type ReverseTransferTxResult struct {
+	TransferTxResult
+	OriginalTransfer Transfer `json:"original_transfer"`
+	// RefundableAmount is what is left to refund of the original transfer after this reversal
+	RefundableAmount int64 `json:"refundable_amount"`
+}
+
+// ReverseTransferTx refunds some or all of a transfer with a compensating transfer linked to it.
+// The original transfer is locked, so that concurrent reversals can't refund more than its amount in total.
+func (store txStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	err := store.execTx(ctx, func(q Querier) error {
+		var err error
+
+		result.OriginalTransfer, err = q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		original := result.OriginalTransfer
+		if original.ReversedTransferID.Valid {
+			return fmt.Errorf("%w: transfer [%d] is a reversal", ErrTransferNotReversible, original.ID)
+		}
+		// the legs of a cross-currency transfer were booked at a rate that has moved on since
+		if original.ExchangeRate.Valid {
+			return fmt.Errorf("%w: transfer [%d] is a cross-currency transfer", ErrTransferNotReversible, original.ID)
+		}
+
+		reversedAmount, err := q.SumReversedAmount(ctx, pgtype.Int8{Int64: original.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+		refundable := original.Amount - reversedAmount
+		if arg.Amount > refundable {
+			return fmt.Errorf("%w: %d left to refund on transfer [%d]", ErrReversalExceedsAmount, refundable, original.ID)
+		}
+
+		reversal, err := q.CreateReversalTransfer(ctx, CreateReversalTransferParams{
+			FromAccountID:      original.ToAccountID,
+			ToAccountID:        original.FromAccountID,
+			Amount:             arg.Amount,
+			ReversedTransferID: pgtype.Int8{Int64: original.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = postTransfer(ctx, q, reversal)
+		if err != nil {
+			return err
+		}
+		result.RefundableAmount = refundable - arg.Amount
+
+		if arg.AfterTransfer != nil {
+			return arg.AfterTransfer(q, result.TransferTxResult)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type RotateSigningKeyTxParams struct {
+	CreateSigningKeyParams
+	// RetiresAt is when the keys replaced by the new key stop verifying tokens
+	RetiresAt time.Time
+}
+
+type RotateSigningKeyTxResult struct {
+	SigningKey  SigningKey
+	RetiredKeys []SigningKey
+}
+
+// RotateSigningKeyTx adds a signing key for new tokens, and retires the other keys of its algorithm once the tokens they signed have expired
+func (store txStore) RotateSigningKeyTx(ctx context.Context, arg RotateSigningKeyTxParams) (RotateSigningKeyTxResult, error) {
+	var result RotateSigningKeyTxResult
+
+	err := store.execTx(ctx, func(q Querier) error {
+		var err error
+
+		result.SigningKey, err = q.CreateSigningKey(ctx, arg.CreateSigningKeyParams)
+		if err != nil {
+			return err
+		}
+
+		result.RetiredKeys, err = q.RetireSigningKeys(ctx, RetireSigningKeysParams{
+			Algorithm: arg.Algorithm,
+			ExceptKid: arg.Kid,
+			RetiresAt: pgtype.Timestamptz{Time: arg.RetiresAt, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	ScheduleStatusActive    = "active"
+	ScheduleStatusPaused    = "paused"
+	ScheduleStatusCompleted = "completed"
+	ScheduleStatusCancelled = "cancelled"
+
+	ScheduledRunStatusSucceeded = "succeeded"
+	ScheduledRunStatusFailed    = "failed"
+
+	// MaxScheduledTransferFailures is the number of failed runs in a row after which a schedule is paused
+	MaxScheduledTransferFailures = 3
+)
+
+// ErrScheduleNotDue is returned when a scheduled transfer is run before its time, or isn't active anymore
+var ErrScheduleNotDue = errors.New("scheduled transfer is not due")
+
+// RunScheduledTransferTxParams contains the input parameters of a scheduled transfer run
+type RunScheduledTransferTxParams struct {
+	ID  int64     `json:"id"`
+	Now time.Time `json:"now"`
+	// AfterTransfer is called when the run has moved the money, q runs its queries in the transaction
+	AfterTransfer func(q Querier, result TransferTxResult) error `json:"-"`
+}
+
+// RunScheduledTransferTxResult is the result of a scheduled transfer run. Transfer is only
+// set when the run succeeded.
+type RunScheduledTransferTxResult struct {
+	ScheduledTransfer ScheduledTransfer    `json:"scheduled_transfer"`
+	Run               ScheduledTransferRun `json:"run"`
+	Transfer          *TransferTxResult    `json:"transfer,omitempty"`
+}
+
+// RunScheduledTransferTx performs a due scheduled transfer and moves the schedule to its next run.
+// When the transfer fails it is rolled back, and the failure is recorded in a second transaction:
+// the run is retried with a growing delay, and the schedule is paused after MaxScheduledTransferFailures.
+func (store txStore) RunScheduledTransferTx(ctx context.Context, arg RunScheduledTransferTxParams) (RunScheduledTransferTxResult, error) {
+	var result RunScheduledTransferTxResult
+
+	err := store.execTx(ctx, func(q Querier) error {
+		scheduledTransfer, err := getDueScheduledTransfer(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		transferResult, err := transfer(ctx, q, TransferTxParams{
+			FromAccountId: scheduledTransfer.FromAccountID,
+			ToAccountID:   scheduledTransfer.ToAccountID,
+			Amount:        scheduledTransfer.Amount,
+			Username:      scheduledTransfer.Owner,
+		})
+		if err != nil {
+			return err
+		}
+		result.Transfer = &transferResult
+
+		if arg.AfterTransfer != nil {
+			if err := arg.AfterTransfer(q, transferResult); err != nil {
+				return err
+			}
+		}
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, CreateScheduledTransferRunParams{
+			ScheduledTransferID: scheduledTransfer.ID,
+			TransferID:          pgtype.Int8{Int64: transferResult.Transfer.ID, Valid: true},
+			Status:              ScheduledRunStatusSucceeded,
+			ScheduledAt:         scheduledTransfer.NextRunAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		update := UpdateScheduledTransferParams{
+			ID:           scheduledTransfer.ID,
+			LastRunAt:    pgtype.Timestamptz{Time: arg.Now, Valid: true},
+			FailureCount: pgtype.Int4{Int32: 0, Valid: true},
+		}
+		if scheduledTransfer.Frequency == utils.FrequencyOnce {
+			update.Status = pgtype.Text{String: ScheduleStatusCompleted, Valid: true}
+		} else {
+			nextRunAt, err := utils.NextRun(scheduledTransfer.Frequency, scheduledTransfer.CronExpression, scheduledTransfer.StartAt.Time, arg.Now)
+			if err != nil {
+				return err
+			}
+			update.NextRunAt = pgtype.Timestamptz{Time: nextRunAt, Valid: true}
+		}
+
+		result.ScheduledTransfer, err = q.UpdateScheduledTransfer(ctx, update)
+		return err
+	})
+	if err == nil || errors.Is(err, ErrScheduleNotDue) || errors.Is(err, ErrRecordNotFound) {
+		return result, err
+	}
+
+	runErr := err
+	result = RunScheduledTransferTxResult{}
+	err = store.execTx(ctx, func(q Querier) error {
+		scheduledTransfer, err := getDueScheduledTransfer(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, CreateScheduledTransferRunParams{
+			ScheduledTransferID: scheduledTransfer.ID,
+			Status:              ScheduledRunStatusFailed,
+			Error:               runErr.Error(),
+			ScheduledAt:         scheduledTransfer.NextRunAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		failureCount := scheduledTransfer.FailureCount + 1
+		update := UpdateScheduledTransferParams{
+			ID:           scheduledTransfer.ID,
+			LastRunAt:    pgtype.Timestamptz{Time: arg.Now, Valid: true},
+			FailureCount: pgtype.Int4{Int32: failureCount, Valid: true},
+		}
+		if failureCount >= MaxScheduledTransferFailures {
+			update.Status = pgtype.Text{String: ScheduleStatusPaused, Valid: true}
+		} else {
+			update.NextRunAt = pgtype.Timestamptz{Time: arg.Now.Add(scheduledTransferRetryDelay(failureCount)), Valid: true}
+		}
+
+		result.ScheduledTransfer, err = q.UpdateScheduledTransfer(ctx, update)
+		return err
+	})
+
+	return result, err
+}
+
+// getDueScheduledTransfer locks a scheduled transfer, and checks that it is still due at arg.Now
+func getDueScheduledTransfer(ctx context.Context, q Querier, arg RunScheduledTransferTxParams) (ScheduledTransfer, error) {
+	scheduledTransfer, err := q.GetScheduledTransferForUpdate(ctx, arg.ID)
+	if err != nil {
+		return ScheduledTransfer{}, err
+	}
+	if scheduledTransfer.Status != ScheduleStatusActive || scheduledTransfer.NextRunAt.Time.After(arg.Now) {
+		return ScheduledTransfer{}, ErrScheduleNotDue
+	}
+	return scheduledTransfer, nil
+}
+
+// scheduledTransferRetryDelay returns how long to wait before retrying a run that failed failureCount times in a row
+func scheduledTransferRetryDelay(failureCount int32) time.Duration {
+	return time.Minute << failureCount
+}
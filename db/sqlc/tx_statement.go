@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// StatementTxParams selects the entries of an account created in [StartTime, EndTime)
+type StatementTxParams struct {
+	AccountID int64
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// StatementLine is an entry together with the account balance right after it
+type StatementLine struct {
+	Entry   Entry `json:"entry"`
+	Balance int64 `json:"balance"`
+}
+
+type StatementTxResult struct {
+	Account        Account         `json:"account"`
+	OpeningBalance int64           `json:"opening_balance"`
+	ClosingBalance int64           `json:"closing_balance"`
+	Lines          []StatementLine `json:"lines"`
+}
+
+// StatementTx builds the statement of an account over a time range. It reads a single snapshot
+// of the account and its entries, so the balances add up without blocking transfers to the account.
+func (store txStore) StatementTx(ctx context.Context, arg StatementTxParams) (StatementTxResult, error) {
+	var result StatementTxResult
+
+	err := store.execReadOnlyTx(ctx, func(q Querier) error {
+		var err error
+
+		result.Account, err = q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		// the opening balance is the current balance minus everything posted since the start
+		postedSince, err := q.SumEntriesSince(ctx, SumEntriesSinceParams{
+			AccountID: arg.AccountID,
+			Since:     pgtype.Timestamptz{Time: arg.StartTime, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		result.OpeningBalance = result.Account.Balance - postedSince
+
+		entries, err := q.ListEntriesBetween(ctx, ListEntriesBetweenParams{
+			AccountID: arg.AccountID,
+			StartTime: pgtype.Timestamptz{Time: arg.StartTime, Valid: true},
+			EndTime:   pgtype.Timestamptz{Time: arg.EndTime, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		balance := result.OpeningBalance
+		result.Lines = make([]StatementLine, 0, len(entries))
+		for _, entry := range entries {
+			balance += entry.Amount
+			result.Lines = append(result.Lines, StatementLine{
+				Entry:   entry,
+				Balance: balance,
+			})
+		}
+		result.ClosingBalance = balance
+		return nil
+	})
+
+	return result, err
+}
@@ -2,6 +2,22 @@ package db
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when an idempotency key is presented again with a different request
+	ErrIdempotencyKeyReused = errors.New("idempotency key already used for a different request")
+	// ErrInsufficientFunds is returned when a transfer would take an account below its overdraft limit
+	ErrInsufficientFunds = errors.New("insufficient funds")
 )
 
 // transferTx performs a money transfer from one account to the other.
@@ -10,6 +26,19 @@ type TransferTxParams struct {
 	FromAccountId int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// Username and IdempotencyKey are optional. When the key is set, a retry of
+	// the same transfer by the same user returns the result of the first one.
+	Username       string `json:"-"`
+	IdempotencyKey string `json:"-"`
+	// AfterTransfer is called when the money has moved, not when an idempotent transfer is replayed.
+	// q runs its queries in the transaction.
+	AfterTransfer func(q Querier, result TransferTxResult) error `json:"-"`
+}
+
+// requestHash identifies the transfer an idempotency key was first used for
+func (arg TransferTxParams) requestHash() string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%d:%d:%d", arg.FromAccountId, arg.ToAccountID, arg.Amount)))
+	return hex.EncodeToString(hash[:])
 }
 
 type TransferTxResult struct {
@@ -18,54 +47,245 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	// SettlementTransfer is the leg that credits the recipient of a cross-currency transfer
+	SettlementTransfer *Transfer `json:"settlement_transfer,omitempty"`
 }
 
-func (store *Store) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+func (store txStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
+	err := store.execTx(ctx, func(q Querier) error {
 		var err error
 
-		transfer, err := q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountId,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
-		if err != nil {
-			return err
+		if arg.IdempotencyKey != "" {
+			replayed, err := claimIdempotencyKey(ctx, q, arg, &result)
+			if err != nil || replayed {
+				return err
+			}
 		}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountId,
-			Amount:    -arg.Amount,
-		})
+		result, err = transfer(ctx, q, arg)
 		if err != nil {
 			return err
 		}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
-		if err != nil {
-			return err
+		if arg.AfterTransfer != nil {
+			if err := arg.AfterTransfer(q, result); err != nil {
+				return err
+			}
 		}
 
-		if arg.FromAccountId < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, _ = addMoney(ctx, q, arg.Amount, arg.FromAccountId, arg.ToAccountID)
-		} else {
-			result.ToAccount, result.FromAccount, _ = addMoney(ctx, q, -arg.Amount, arg.ToAccountID, arg.FromAccountId)
+		if arg.IdempotencyKey != "" {
+			return saveIdempotencyKeyResponse(ctx, q, arg, result)
 		}
-
-		result.Transfer = transfer
-
 		return nil
 	})
 
 	return result, err
 }
 
-func addMoney(ctx context.Context, q *Queries, amount int64, accountID1 int64, accountID2 int64) (account1 Account, account2 Account, err error) {
+// transfer moves money between two accounts with the given querier. Accounts in
+// different currencies are settled through the fx position accounts.
+func transfer(ctx context.Context, q Querier, arg TransferTxParams) (TransferTxResult, error) {
+	fromAccount, err := q.GetAccount(ctx, arg.FromAccountId)
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+	toAccount, err := q.GetAccount(ctx, arg.ToAccountID)
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+	if fromAccount.Currency != toAccount.Currency {
+		return fxTransfer(ctx, q, arg, fromAccount, toAccount)
+	}
+
+	transfer, err := q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountId,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+	})
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+	return postTransfer(ctx, q, transfer)
+}
+
+// fxTransfer converts the amount at the exchange rate in effect and books it as two legs:
+// from the customer to the fx position account of the source currency, and from the fx
+// position account of the target currency to the customer. Both legs record the rate.
+func fxTransfer(ctx context.Context, q Querier, arg TransferTxParams, fromAccount Account, toAccount Account) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	fxRate, err := q.GetFxRate(ctx, GetFxRateParams{
+		FromCurrency: fromAccount.Currency,
+		ToCurrency:   toAccount.Currency,
+		At:           pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return result, fmt.Errorf("%w: %s to %s", ErrNoFxRate, fromAccount.Currency, toAccount.Currency)
+		}
+		return result, err
+	}
+	toAmount, err := ConvertAmount(arg.Amount, fxRate.Rate)
+	if err != nil {
+		return result, err
+	}
+	if toAmount <= 0 {
+		return result, ErrAmountTooSmall
+	}
+
+	fromFxAccount, err := q.GetSystemAccount(ctx, GetSystemAccountParams{Kind: AccountKindFx, Currency: fromAccount.Currency})
+	if err != nil {
+		return result, fmt.Errorf("failed to get %s fx account: %w", fromAccount.Currency, err)
+	}
+	toFxAccount, err := q.GetSystemAccount(ctx, GetSystemAccountParams{Kind: AccountKindFx, Currency: toAccount.Currency})
+	if err != nil {
+		return result, fmt.Errorf("failed to get %s fx account: %w", toAccount.Currency, err)
+	}
+
+	// lock all four accounts in id order up front, so that concurrent
+	// transfers in opposite directions cannot deadlock between the legs
+	ids := []int64{fromAccount.ID, toAccount.ID, fromFxAccount.ID, toFxAccount.ID}
+	slices.Sort(ids)
+	for _, id := range ids {
+		if _, err := q.GetAccountForUpdate(ctx, id); err != nil {
+			return result, err
+		}
+	}
+
+	debit, err := q.CreateFxTransfer(ctx, CreateFxTransferParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   fromFxAccount.ID,
+		Amount:        arg.Amount,
+		ExchangeRate:  fxRate.Rate,
+		FxRateID:      pgtype.Int8{Int64: fxRate.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+	debitResult, err := postTransfer(ctx, q, debit)
+	if err != nil {
+		return result, err
+	}
+
+	credit, err := q.CreateFxTransfer(ctx, CreateFxTransferParams{
+		FromAccountID:    toFxAccount.ID,
+		ToAccountID:      toAccount.ID,
+		Amount:           toAmount,
+		ExchangeRate:     fxRate.Rate,
+		FxRateID:         pgtype.Int8{Int64: fxRate.ID, Valid: true},
+		SourceTransferID: pgtype.Int8{Int64: debit.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+	creditResult, err := postTransfer(ctx, q, credit)
+	if err != nil {
+		return result, err
+	}
+
+	result = TransferTxResult{
+		Transfer:           debitResult.Transfer,
+		FromAccount:        debitResult.FromAccount,
+		ToAccount:          creditResult.ToAccount,
+		FromEntry:          debitResult.FromEntry,
+		ToEntry:            creditResult.ToEntry,
+		SettlementTransfer: &creditResult.Transfer,
+	}
+	return result, nil
+}
+
+// postTransfer adds the entries of a transfer record and updates both account balances
+func postTransfer(ctx context.Context, q Querier, transfer Transfer) (result TransferTxResult, err error) {
+	result.Transfer = transfer
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: transfer.FromAccountID,
+		Amount:    -transfer.Amount,
+	})
+	if err != nil {
+		return
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: transfer.ToAccountID,
+		Amount:    transfer.Amount,
+	})
+	if err != nil {
+		return
+	}
+
+	if transfer.FromAccountID < transfer.ToAccountID {
+		result.FromAccount, result.ToAccount, err = addMoney(ctx, q, transfer.Amount, transfer.FromAccountID, transfer.ToAccountID)
+	} else {
+		result.ToAccount, result.FromAccount, err = addMoney(ctx, q, -transfer.Amount, transfer.ToAccountID, transfer.FromAccountID)
+	}
+	if err != nil {
+		return
+	}
+
+	// the balances are checked after the update, so that both rows stay locked in id order
+	err = checkFunds(result.FromAccount)
+	return
+}
+
+// claimIdempotencyKey records the idempotency key of arg. If the key was already used
+// by a committed transfer, it loads that transfer's result instead and reports it as replayed.
+func claimIdempotencyKey(ctx context.Context, q Querier, arg TransferTxParams, result *TransferTxResult) (bool, error) {
+	requestHash := arg.requestHash()
+	_, err := q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+		Username:    arg.Username,
+		Key:         arg.IdempotencyKey,
+		RequestHash: requestHash,
+	})
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, ErrRecordNotFound) {
+		return false, err
+	}
+
+	// the insert did nothing, so the key belongs to an earlier request
+	key, err := q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		Username: arg.Username,
+		Key:      arg.IdempotencyKey,
+	})
+	if err != nil {
+		return false, err
+	}
+	if key.RequestHash != requestHash {
+		return false, ErrIdempotencyKeyReused
+	}
+	if err := json.Unmarshal(key.Response, result); err != nil {
+		return false, fmt.Errorf("failed to unmarshal idempotent response: %w", err)
+	}
+	return true, nil
+}
+
+func saveIdempotencyKeyResponse(ctx context.Context, q Querier, arg TransferTxParams, result TransferTxResult) error {
+	response, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotent response: %w", err)
+	}
+	_, err = q.UpdateIdempotencyKeyResponse(ctx, UpdateIdempotencyKeyResponseParams{
+		Response: response,
+		Username: arg.Username,
+		Key:      arg.IdempotencyKey,
+	})
+	return err
+}
+
+// checkFunds makes sure a customer account did not go below its overdraft limit.
+// System accounts are the bank's side of the ledger and may go negative.
+func checkFunds(account Account) error {
+	if !account.IsSystem() && account.Balance < -account.OverdraftLimit {
+		return fmt.Errorf("%w: account [%d] balance %d is below the overdraft limit of %d", ErrInsufficientFunds, account.ID, account.Balance, account.OverdraftLimit)
+	}
+	return nil
+}
+
+func addMoney(ctx context.Context, q Querier, amount int64, accountID1 int64, accountID2 int64) (account1 Account, account2 Account, err error) {
 	account1, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
 		ID:     accountID1,
 		Amount: -amount,
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+)
+
+type EnrollTOTPTxParams struct {
+	Username           string
+	Secret             string
+	RecoveryCodeHashes []string
+}
+
+type EnrollTOTPTxResult struct {
+	Credential TOTPCredential
+}
+
+// EnrollTOTPTx stores a new unconfirmed totp secret of a user, and replaces the recovery codes of the user
+func (store txStore) EnrollTOTPTx(ctx context.Context, arg EnrollTOTPTxParams) (EnrollTOTPTxResult, error) {
+	var result EnrollTOTPTxResult
+
+	err := store.execTx(ctx, func(q Querier) error {
+		var err error
+
+		result.Credential, err = q.UpsertTOTPCredential(ctx, UpsertTOTPCredentialParams{
+			Username: arg.Username,
+			Secret:   arg.Secret,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteRecoveryCodes(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+		for _, codeHash := range arg.RecoveryCodeHashes {
+			_, err = q.CreateRecoveryCode(ctx, CreateRecoveryCodeParams{
+				Username: arg.Username,
+				CodeHash: codeHash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// DisableTOTPTx removes the totp secret and the recovery codes of a user
+func (store txStore) DisableTOTPTx(ctx context.Context, username string) error {
+	return store.execTx(ctx, func(q Querier) error {
+		err := q.DeleteTOTPCredential(ctx, username)
+		if err != nil {
+			return err
+		}
+		return q.DeleteRecoveryCodes(ctx, username)
+	})
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type UpdateUserTxParams struct {
+	UpdateUserParams
+	// CurrentSessionID is the session the change is made from. When the password
+	// changes, every other session of the user is blocked, and a pending email change
+	// is dropped unless it is made along with the password.
+	CurrentSessionID uuid.UUID
+	// CancelEmailChange drops the pending email change of the user, e.g. when the email is set back to the current one.
+	CancelEmailChange bool
+	// AfterUpdate is called with the updated user, when it is set. q runs its queries in the transaction.
+	AfterUpdate func(q Querier, user User) error
+}
+
+type UpdateUserTxResult struct {
+	User            User
+	BlockedSessions int64
+}
+
+func (store txStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error) {
+	var result UpdateUserTxResult
+
+	err := store.execTx(ctx, func(q Querier) error {
+		var err error
+
+		// a pending email that has expired unverified no longer holds the address
+		if arg.PendingEmail.Valid {
+			err = q.ReleaseExpiredPendingEmail(ctx, arg.PendingEmail)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.User, err = q.UpdateUser(ctx, arg.UpdateUserParams)
+		if err != nil {
+			return err
+		}
+
+		if arg.Password.Valid {
+			result.BlockedSessions, err = q.BlockUserSessions(ctx, BlockUserSessionsParams{
+				Username: result.User.Username,
+				ExceptID: arg.CurrentSessionID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		cancel := arg.CancelEmailChange || (arg.Password.Valid && !arg.PendingEmail.Valid)
+		if cancel && result.User.PendingEmail.Valid {
+			result.User, err = cancelEmailChange(ctx, q, result.User)
+			if err != nil {
+				return err
+			}
+		}
+
+		if arg.AfterUpdate != nil {
+			return arg.AfterUpdate(q, result.User)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// cancelEmailChange drops the pending email of the user, and uses up the links sent to verify it.
+func cancelEmailChange(ctx context.Context, q Querier, user User) (User, error) {
+	err := q.InvalidateVerifyEmails(ctx, InvalidateVerifyEmailsParams{
+		Username: user.Username,
+		Email:    user.PendingEmail.String,
+	})
+	if err != nil {
+		return User{}, err
+	}
+	return q.CancelUserEmailChange(ctx, user.Username)
+}
@@ -9,6 +9,12 @@ import (
 type VerifyEmailTxParams struct {
 	EmailId    int64
 	SecretCode string
+	// AfterEmailChange is called when the verified email replaces the previous email of the user.
+	// q runs its queries in the transaction.
+	AfterEmailChange func(q Querier, user User, previousEmail string) error
+	// AfterVerify is called with the user once the email is verified, whether it was new or not.
+	// q runs its queries in the transaction.
+	AfterVerify func(q Querier, user User) error
 }
 
 type VerifyEmailTxResult struct {
@@ -16,10 +22,10 @@ type VerifyEmailTxResult struct {
 	VerifyEmail VerifyEmail
 }
 
-func (store *Store) VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error) {
+func (store txStore) VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error) {
 	var result VerifyEmailTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
+	err := store.execTx(ctx, func(q Querier) error {
 		var err error
 
 		result.VerifyEmail, err = q.UpdateVerifyEmail(ctx, UpdateVerifyEmailParams{
@@ -30,15 +36,48 @@ func (store *Store) VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams)
 			return err
 		}
 
-		result.User, err = q.UpdateUser(ctx, UpdateUserParams{
-			Username: result.VerifyEmail.Username,
-			IsEmailVerified: pgtype.Bool{
-				Bool:  true,
-				Valid: true,
-			},
-		})
-		return err
+		user, err := q.GetUser(ctx, result.VerifyEmail.Username)
+		if err != nil {
+			return err
+		}
+
+		if result.VerifyEmail.Email == user.Email {
+			result.User, err = q.UpdateUser(ctx, UpdateUserParams{
+				Username: result.VerifyEmail.Username,
+				IsEmailVerified: pgtype.Bool{
+					Bool:  true,
+					Valid: true,
+				},
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			// the link was sent to a changed email, which only replaces the current one now.
+			// It fails when the user has changed the email again since.
+			result.User, err = q.ConfirmUserEmail(ctx, ConfirmUserEmailParams{
+				Username: result.VerifyEmail.Username,
+				PendingEmail: pgtype.Text{
+					String: result.VerifyEmail.Email,
+					Valid:  true,
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			if arg.AfterEmailChange != nil {
+				if err := arg.AfterEmailChange(q, result.User, user.Email); err != nil {
+					return err
+				}
+			}
+		}
+
+		if arg.AfterVerify != nil {
+			return arg.AfterVerify(q, result.User)
+		}
+		return nil
 	})
 
 	return result, err
-}
\ No newline at end of file
+}
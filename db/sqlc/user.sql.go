@@ -11,16 +11,87 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const cancelUserEmailChange = `-- name: CancelUserEmailChange :one
+UPDATE users
+SET
+  pending_email = NULL,
+  pending_email_expires_at = NULL
+WHERE username = $1
+RETURNING username, password, fullname, email, password_changed_at, created_at, is_email_verified, role, failed_login_attempts, locked_until, pending_email, locale, pending_email_expires_at
+`
+
+func (q *Queries) CancelUserEmailChange(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRow(ctx, cancelUserEmailChange, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Password,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.PendingEmail,
+		&i.Locale,
+		&i.PendingEmailExpiresAt,
+	)
+	return i, err
+}
+
+const confirmUserEmail = `-- name: ConfirmUserEmail :one
+UPDATE users
+SET
+  email = pending_email,
+  pending_email = NULL,
+  pending_email_expires_at = NULL,
+  is_email_verified = true
+WHERE
+  username = $1
+  AND pending_email = $2
+  AND pending_email_expires_at > now()
+RETURNING username, password, fullname, email, password_changed_at, created_at, is_email_verified, role, failed_login_attempts, locked_until, pending_email, locale, pending_email_expires_at
+`
+
+type ConfirmUserEmailParams struct {
+	Username     string      `json:"username"`
+	PendingEmail pgtype.Text `json:"pending_email"`
+}
+
+func (q *Queries) ConfirmUserEmail(ctx context.Context, arg ConfirmUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, confirmUserEmail, arg.Username, arg.PendingEmail)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Password,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.PendingEmail,
+		&i.Locale,
+		&i.PendingEmailExpiresAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   username,
   password,
   fullname,
-  email
+  email,
+  locale
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5
 )
-RETURNING username, password, fullname, email, password_changed_at, created_at, is_email_verified
+RETURNING username, password, fullname, email, password_changed_at, created_at, is_email_verified, role, failed_login_attempts, locked_until, pending_email, locale, pending_email_expires_at
 `
 
 type CreateUserParams struct {
@@ -28,6 +99,7 @@ type CreateUserParams struct {
 	Password string `json:"password"`
 	Fullname string `json:"fullname"`
 	Email    string `json:"email"`
+	Locale   string `json:"locale"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -36,6 +108,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Password,
 		arg.Fullname,
 		arg.Email,
+		arg.Locale,
 	)
 	var i User
 	err := row.Scan(
@@ -46,12 +119,18 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
 		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.PendingEmail,
+		&i.Locale,
+		&i.PendingEmailExpiresAt,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, password, fullname, email, password_changed_at, created_at, is_email_verified FROM users
+SELECT username, password, fullname, email, password_changed_at, created_at, is_email_verified, role, failed_login_attempts, locked_until, pending_email, locale, pending_email_expires_at FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -66,10 +145,83 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
 		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.PendingEmail,
+		&i.Locale,
+		&i.PendingEmailExpiresAt,
 	)
 	return i, err
 }
 
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT username, password, fullname, email, password_changed_at, created_at, is_email_verified, role, failed_login_attempts, locked_until, pending_email, locale, pending_email_expires_at FROM users
+WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Password,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.PendingEmail,
+		&i.Locale,
+		&i.PendingEmailExpiresAt,
+	)
+	return i, err
+}
+
+const getUserByPendingEmail = `-- name: GetUserByPendingEmail :one
+SELECT username, password, fullname, email, password_changed_at, created_at, is_email_verified, role, failed_login_attempts, locked_until, pending_email, locale, pending_email_expires_at FROM users
+WHERE pending_email = $1 AND pending_email_expires_at > now() LIMIT 1
+`
+
+func (q *Queries) GetUserByPendingEmail(ctx context.Context, pendingEmail pgtype.Text) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByPendingEmail, pendingEmail)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Password,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.PendingEmail,
+		&i.Locale,
+		&i.PendingEmailExpiresAt,
+	)
+	return i, err
+}
+
+const releaseExpiredPendingEmail = `-- name: ReleaseExpiredPendingEmail :exec
+UPDATE users
+SET
+  pending_email = NULL,
+  pending_email_expires_at = NULL
+WHERE
+  pending_email = $1
+  AND pending_email_expires_at <= now()
+`
+
+func (q *Queries) ReleaseExpiredPendingEmail(ctx context.Context, pendingEmail pgtype.Text) error {
+	_, err := q.db.Exec(ctx, releaseExpiredPendingEmail, pendingEmail)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET
@@ -77,19 +229,27 @@ SET
   password_changed_at = COALESCE($2, password_changed_at),
   fullname = COALESCE($3, fullname),
   email = COALESCE($4, email),
-  is_email_verified = COALESCE($5, is_email_verified)
+  pending_email = COALESCE($5, pending_email),
+  pending_email_expires_at = COALESCE($6, pending_email_expires_at),
+  is_email_verified = COALESCE($7, is_email_verified),
+  role = COALESCE($8, role),
+  locale = COALESCE($9, locale)
 WHERE
-  username = $6
-RETURNING username, password, fullname, email, password_changed_at, created_at, is_email_verified
+  username = $10
+RETURNING username, password, fullname, email, password_changed_at, created_at, is_email_verified, role, failed_login_attempts, locked_until, pending_email, locale, pending_email_expires_at
 `
 
 type UpdateUserParams struct {
-	Password          pgtype.Text        `json:"password"`
-	PasswordChangedAt pgtype.Timestamptz `json:"password_changed_at"`
-	Fullname          pgtype.Text        `json:"fullname"`
-	Email             pgtype.Text        `json:"email"`
-	IsEmailVerified   pgtype.Bool        `json:"is_email_verified"`
-	Username          string             `json:"username"`
+	Password              pgtype.Text        `json:"password"`
+	PasswordChangedAt     pgtype.Timestamptz `json:"password_changed_at"`
+	Fullname              pgtype.Text        `json:"fullname"`
+	Email                 pgtype.Text        `json:"email"`
+	PendingEmail          pgtype.Text        `json:"pending_email"`
+	PendingEmailExpiresAt pgtype.Timestamptz `json:"pending_email_expires_at"`
+	IsEmailVerified       pgtype.Bool        `json:"is_email_verified"`
+	Role                  pgtype.Text        `json:"role"`
+	Locale                pgtype.Text        `json:"locale"`
+	Username              string             `json:"username"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -98,7 +258,11 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.PasswordChangedAt,
 		arg.Fullname,
 		arg.Email,
+		arg.PendingEmail,
+		arg.PendingEmailExpiresAt,
 		arg.IsEmailVerified,
+		arg.Role,
+		arg.Locale,
 		arg.Username,
 	)
 	var i User
@@ -110,6 +274,12 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
 		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.PendingEmail,
+		&i.Locale,
+		&i.PendingEmailExpiresAt,
 	)
 	return i, err
 }
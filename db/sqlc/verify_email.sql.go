@@ -40,6 +40,26 @@ func (q *Queries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailPa
 	return i, err
 }
 
+const invalidateVerifyEmails = `-- name: InvalidateVerifyEmails :exec
+UPDATE verify_emails
+SET
+    is_used = TRUE
+WHERE
+    username = $1
+    AND email = $2
+    AND is_used = FALSE
+`
+
+type InvalidateVerifyEmailsParams struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+func (q *Queries) InvalidateVerifyEmails(ctx context.Context, arg InvalidateVerifyEmailsParams) error {
+	_, err := q.db.Exec(ctx, invalidateVerifyEmails, arg.Username, arg.Email)
+	return err
+}
+
 const updateVerifyEmail = `-- name: UpdateVerifyEmail :one
 UPDATE verify_emails
 SET
@@ -11,6 +11,7 @@ import (
 
 	"github.com/absk07/Go-Bank/api/grpc_api"
 	"github.com/absk07/Go-Bank/api/rest_api"
+	"github.com/absk07/Go-Bank/api/web"
 	db "github.com/absk07/Go-Bank/db/sqlc"
 	"github.com/absk07/Go-Bank/pb"
 	"github.com/absk07/Go-Bank/utils"
@@ -75,9 +76,11 @@ func main() {
 	log.Print(msg)
 
 	runTaskProcessor(ctx, waitGroup, config, redisOpt, store)
-	// runGinServer(config, store, taskDistributor)
-	runGatewayServer(ctx, waitGroup, config, store, taskDistributor)
-	runGrpcServer(ctx, waitGroup, config, store, taskDistributor)
+	runTaskScheduler(ctx, waitGroup, redisOpt)
+	runOutboxRelay(ctx, waitGroup, store, taskDistributor)
+	// runGinServer(config, store)
+	runGatewayServer(ctx, waitGroup, config, store)
+	runGrpcServer(ctx, waitGroup, config, store)
 
 	err = waitGroup.Wait()
 	if err != nil {
@@ -96,13 +99,40 @@ func runDBMigration(migrationURL string, dbSource string) {
 	log.Print("DB migrated successfully")
 }
 
-func runTaskProcessor(ctx context.Context, wg *errgroup.Group, config utils.Config, redisOpt asynq.RedisClientOpt, store *db.Store) {
-	mailer := utils.NewGmailSender(config.EmailSenderName, config.EmailSenderAddress, config.EmailSenderPassword)
-	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer)
+func runTaskProcessor(ctx context.Context, wg *errgroup.Group, config utils.Config, redisOpt asynq.RedisClientOpt, store db.Store) {
+	var mailer utils.EmailSender
+	switch config.EmailSender {
+	case "", utils.EmailSenderGmail:
+		mailer = utils.NewGmailSender(config.EmailSenderName, config.EmailSenderAddress, config.EmailSenderPassword)
+	case utils.EmailSenderSMTP:
+		var err error
+		mailer, err = utils.NewSMTPSender(utils.SMTPConfig{
+			Host:        config.SMTPHost,
+			Port:        config.SMTPPort,
+			TLSMode:     config.SMTPTLSMode,
+			Auth:        config.SMTPAuth,
+			Username:    config.SMTPUsername,
+			Password:    config.SMTPPassword,
+			FromName:    config.EmailSenderName,
+			FromAddress: config.EmailSenderAddress,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("cannot create smtp email sender")
+		}
+	default:
+		log.Fatal().Msgf("unsupported email sender: %s", config.EmailSender)
+	}
+
+	templates, err := utils.LoadEmailTemplates(os.DirFS(config.EmailTemplateDir), config.PublicBaseURL)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot load email templates")
+	}
+
+	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer, templates)
 
 	log.Info().Msg("starting task processor")
 
-	err := taskProcessor.Start()
+	err = taskProcessor.Start()
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to start task processor")
 	}
@@ -118,12 +148,50 @@ func runTaskProcessor(ctx context.Context, wg *errgroup.Group, config utils.Conf
 	})
 }
 
-func runGrpcServer(ctx context.Context, wg *errgroup.Group, config utils.Config, store *db.Store, taskDistributor worker.TaskDistributor) {
-	server := grpc_api.NewServer(config, store, taskDistributor)
+func runTaskScheduler(ctx context.Context, wg *errgroup.Group, redisOpt asynq.RedisClientOpt) {
+	taskScheduler := worker.NewRedisTaskScheduler(redisOpt)
+
+	log.Info().Msg("starting task scheduler")
+
+	err := taskScheduler.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to start task scheduler")
+	}
+
+	wg.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("gracefully shutdown task scheduler")
 
-	grpcLogger := grpc.UnaryInterceptor(utils.GrpcLogger)
+		taskScheduler.Shutdown()
+		log.Info().Msg("task scheduler is stopped")
+
+		return nil
+	})
+}
 
-	grpcServer := grpc.NewServer(grpcLogger)
+func runOutboxRelay(ctx context.Context, wg *errgroup.Group, store db.Store, taskDistributor worker.TaskDistributor) {
+	outboxRelay := worker.NewOutboxRelay(store, taskDistributor)
+
+	log.Info().Msg("starting outbox relay")
+
+	wg.Go(func() error {
+		outboxRelay.Start(ctx)
+		log.Info().Msg("outbox relay is stopped")
+
+		return nil
+	})
+}
+
+func runGrpcServer(ctx context.Context, wg *errgroup.Group, config utils.Config, store db.Store) {
+	server, err := grpc_api.NewServer(config, store)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create server")
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(server.UnaryInterceptors()...),
+		grpc.ChainStreamInterceptor(server.StreamAuthInterceptor),
+	)
 	pb.RegisterGoBankServer(grpcServer, server)
 	reflection.Register(grpcServer)
 
@@ -151,25 +219,36 @@ func runGrpcServer(ctx context.Context, wg *errgroup.Group, config utils.Config,
 	})
 }
 
-func runGatewayServer(ctx context.Context, wg *errgroup.Group, config utils.Config, store *db.Store, taskDistributor worker.TaskDistributor) {
-	server := grpc_api.NewServer(config, store, taskDistributor)
+func runGatewayServer(ctx context.Context, wg *errgroup.Group, config utils.Config, store db.Store) {
+	server, err := grpc_api.NewServer(config, store)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create server")
+	}
 	grpc_mux := runtime.NewServeMux(
-		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
-			MarshalOptions: protojson.MarshalOptions{
-				UseProtoNames: true,
-			},
-			UnmarshalOptions: protojson.UnmarshalOptions{
-				DiscardUnknown: true,
+		runtime.WithIncomingHeaderMatcher(grpc_api.HeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(grpc_api.OutgoingHeaderMatcher),
+		// HTTPBodyMarshaler writes the google.api.HttpBody responses as they are, instead of as JSON
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{
+			Marshaler: &runtime.JSONPb{
+				MarshalOptions: protojson.MarshalOptions{
+					UseProtoNames: true,
+				},
+				UnmarshalOptions: protojson.UnmarshalOptions{
+					DiscardUnknown: true,
+				},
 			},
 		}),
 	)
 
-	err := pb.RegisterGoBankHandlerServer(ctx, grpc_mux, server)
+	// the gateway calls the server through an in-process connection, so that its calls go through the same interceptors
+	conn := grpc_api.NewInProcessConn(server, server.UnaryInterceptors()...)
+	err = pb.RegisterGoBankHandlerClient(ctx, grpc_mux, pb.NewGoBankClient(conn))
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot register handler server")
+		log.Fatal().Err(err).Msg("cannot register handler client")
 	}
 	mux := http.NewServeMux()
 	mux.Handle("/", grpc_mux)
+	mux.Handle(web.ResetPasswordPath, web.ResetPasswordPage())
 
 	httpServer := &http.Server{
 		Handler: utils.HttpLogger(mux),
@@ -204,10 +283,13 @@ func runGatewayServer(ctx context.Context, wg *errgroup.Group, config utils.Conf
 	})
 }
 
-func runGinServer(config utils.Config, store *db.Store, taskDistributor worker.TaskDistributor) {
-	server := rest_api.NewServer(config, store, taskDistributor)
+func runGinServer(config utils.Config, store db.Store) {
+	server, err := rest_api.NewServer(config, store)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create server")
+	}
 
-	err := server.Start(config.HTTP_Port)
+	err = server.Start(config.HTTP_Port)
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot start gin HTTP server")
 	}
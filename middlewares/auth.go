@@ -2,7 +2,6 @@ package middlewares
 
 import (
 	// "fmt"
-	"log"
 	"net/http"
 	"strings"
 
@@ -10,11 +9,13 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func IsAuthenticated(ctx *gin.Context) {
-	config, err := utils.LoadConfig()
-	if err != nil {
-		log.Fatal("Problem loading configs...")
+func IsAuthenticated(tokenMaker utils.TokenMaker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authenticate(ctx, tokenMaker)
 	}
+}
+
+func authenticate(ctx *gin.Context, tokenMaker utils.TokenMaker) {
 	auth_header := ctx.Request.Header.Get("Authorization")
 	fields := strings.Fields(auth_header)
 	if len(fields) < 2 {
@@ -39,15 +40,16 @@ func IsAuthenticated(ctx *gin.Context) {
 		})
 		return
 	}
-	_, username, err := utils.VerifyToken(token, config.Secret)
+	payload, err := tokenMaker.VerifyToken(token)
 	// fmt.Println(err)
-	if err != nil {
+	if err != nil || payload.Type != utils.TokenTypeAccess {
 		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 			"success": false,
 			"error":   "User Unauthenticated!",
 		})
 		return
 	}
-	ctx.Set("username", username)
+	ctx.Set("username", payload.Username)
+	ctx.Set("role", payload.Role)
 	ctx.Next()
 }
\ No newline at end of file
@@ -7,24 +7,38 @@ import (
 )
 
 type Config struct {
-	Env                   string        `mapstructure:"Env"`
-	DBDriver              string        `mapstructure:"DB_DRIVER"`
-	DBSource              string        `mapstructure:"DB_SOURCE"`
-	DBMigrationURL        string        `mapstructure:"DB_MIGRATION_URL"`
-	HTTP_Port             string        `mapstructure:"HTTP_PORT"`
-	GRPC_Port             string        `mapstructure:"GRPC_PORT"`
-	Redis_Port            string        `mapstructure:"REDIS_PORT"`
-	Secret                string        `mapstructure:"SECRET"`
-	TokenDuration         time.Duration `mapstructure:"TOKEN_DURATION"`
-	RefereshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
-	EmailSenderName       string        `mapstructure:"EMAIL_SENDER_NAME"`
-	EmailSenderAddress    string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
-	EmailSenderPassword   string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
+	Env                     string        `mapstructure:"Env"`
+	DBDriver                string        `mapstructure:"DB_DRIVER"`
+	DBSource                string        `mapstructure:"DB_SOURCE"`
+	DBMigrationURL          string        `mapstructure:"DB_MIGRATION_URL"`
+	HTTP_Port               string        `mapstructure:"HTTP_PORT"`
+	GRPC_Port               string        `mapstructure:"GRPC_PORT"`
+	Redis_Port              string        `mapstructure:"REDIS_PORT"`
+	Secret                  string        `mapstructure:"SECRET"`
+	TokenAlgorithm          string        `mapstructure:"TOKEN_ALGORITHM"`
+	TokenPrivateKey         string        `mapstructure:"TOKEN_PRIVATE_KEY"`
+	SigningKeyEncryptionKey string        `mapstructure:"SIGNING_KEY_ENCRYPTION_KEY"`
+	TokenDuration           time.Duration `mapstructure:"TOKEN_DURATION"`
+	RefereshTokenDuration   time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	EmailSenderName         string        `mapstructure:"EMAIL_SENDER_NAME"`
+	EmailSenderAddress      string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	EmailSenderPassword     string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
+	EmailSender             string        `mapstructure:"EMAIL_SENDER"`
+	EmailTemplateDir        string        `mapstructure:"EMAIL_TEMPLATE_DIR"`
+	PublicBaseURL           string        `mapstructure:"PUBLIC_BASE_URL"`
+	SMTPHost                string        `mapstructure:"SMTP_HOST"`
+	SMTPPort                int           `mapstructure:"SMTP_PORT"`
+	SMTPTLSMode             string        `mapstructure:"SMTP_TLS_MODE"`
+	SMTPAuth                string        `mapstructure:"SMTP_AUTH"`
+	SMTPUsername            string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword            string        `mapstructure:"SMTP_PASSWORD"`
 }
 
 func LoadConfig() (config Config, err error) {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
+	viper.SetDefault("EMAIL_TEMPLATE_DIR", "templates/email")
+	viper.SetDefault("PUBLIC_BASE_URL", "http://localhost:8080")
 	err = viper.ReadInConfig()
 	if err != nil {
 		return
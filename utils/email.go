@@ -1,51 +1,37 @@
 package utils
 
-import (
-	"fmt"
-	"net/smtp"
-
-	"github.com/jordan-wright/email"
-)
-
 const (
-	smtpAuthAddress   = "smtp.gmail.com"
-	smtpServerAddress = "smtp.gmail.com:587"
+	// EmailSenderGmail sends emails through a Gmail account, EmailSenderSMTP through the SMTP server set in config
+	EmailSenderGmail = "gmail"
+	EmailSenderSMTP  = "smtp"
+
+	gmailSMTPHost = "smtp.gmail.com"
+	gmailSMTPPort = 587
 )
 
-type EmailSender interface {
-	SendEmail(subject string, content string, to []string, cc []string, bcc []string, attachFiles []string) error
+// EmailContent is an email sent with an HTML part and a plain-text part, for clients that do not show HTML
+type EmailContent struct {
+	Subject string
+	HTML    string
+	Text    string
 }
 
-type GmailSender struct {
-	name              string
-	fromEmailAddress  string
-	fromEmailPassword string
+type EmailSender interface {
+	SendEmail(content EmailContent, to []string, cc []string, bcc []string, attachFiles []string) error
 }
 
+// NewGmailSender creates a sender for a Gmail account, signing in with an app password
 func NewGmailSender(name string, fromEmailAddress string, fromEmailPassword string) EmailSender {
-	return &GmailSender{
-		name:              name,
-		fromEmailAddress:  fromEmailAddress,
-		fromEmailPassword: fromEmailPassword,
+	return &SMTPSender{
+		config: SMTPConfig{
+			Host:        gmailSMTPHost,
+			Port:        gmailSMTPPort,
+			TLSMode:     SMTPTLSModeStartTLS,
+			Auth:        SMTPAuthPlain,
+			Username:    fromEmailAddress,
+			Password:    fromEmailPassword,
+			FromName:    name,
+			FromAddress: fromEmailAddress,
+		},
 	}
 }
-
-func (sender *GmailSender) SendEmail(subject string, content string, to []string, cc []string, bcc []string, attachFiles []string) error {
-	e := email.NewEmail()
-	e.From = fmt.Sprintf("%s <%s>", sender.name, sender.fromEmailAddress)
-	e.Subject = subject
-	e.HTML = []byte(content)
-	e.To = to
-	e.Cc = cc
-	e.Bcc = bcc
-
-	for _, f := range attachFiles {
-		_, err := e.AttachFile(f)
-		if err != nil {
-			return fmt.Errorf("failed to attach file %s: %w", f, err)
-		}
-	}
-
-	smtpAuth := smtp.PlainAuth("", sender.fromEmailAddress, sender.fromEmailPassword, smtpAuthAddress)
-	return e.Send(smtpServerAddress, smtpAuth)
-}
\ No newline at end of file
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// EmailTemplates renders the emails sent to users. Every message type is a pair of files per locale:
+// <locale>/<name>.html, an html/template for the HTML part, and <locale>/<name>.txt, a text/template
+// for the plain-text part which also defines the "subject" template.
+type EmailTemplates struct {
+	baseURL *url.URL
+	html    map[emailTemplateKey]*htmltemplate.Template
+	text    map[emailTemplateKey]*texttemplate.Template
+}
+
+type emailTemplateKey struct {
+	locale string
+	name   string
+}
+
+// LoadEmailTemplates parses the templates in fsys. Links in the emails point to baseURL, the public address of the bank.
+func LoadEmailTemplates(fsys fs.FS, baseURL string) (*EmailTemplates, error) {
+	publicURL, err := url.Parse(baseURL)
+	if err != nil || publicURL.Scheme == "" || publicURL.Host == "" {
+		return nil, fmt.Errorf("invalid public base url: %q", baseURL)
+	}
+
+	templates := &EmailTemplates{
+		baseURL: publicURL,
+		html:    map[emailTemplateKey]*htmltemplate.Template{},
+		text:    map[emailTemplateKey]*texttemplate.Template{},
+	}
+
+	locales, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email templates: %w", err)
+	}
+	for _, locale := range locales {
+		if !locale.IsDir() {
+			continue
+		}
+		if !IsSupportedLocale(locale.Name()) {
+			return nil, fmt.Errorf("email templates of unsupported locale: %s", locale.Name())
+		}
+
+		files, err := fs.Glob(fsys, path.Join(locale.Name(), "*.html"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			key := emailTemplateKey{
+				locale: locale.Name(),
+				name:   strings.TrimSuffix(path.Base(file), ".html"),
+			}
+
+			html, err := htmltemplate.ParseFS(fsys, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse email template: %w", err)
+			}
+			text, err := texttemplate.ParseFS(fsys, path.Join(key.locale, key.name+".txt"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse email template: %w", err)
+			}
+			if text.Lookup("subject") == nil {
+				return nil, fmt.Errorf("email template %s/%s.txt does not define a subject", key.locale, key.name)
+			}
+
+			templates.html[key] = html
+			templates.text[key] = text
+		}
+	}
+
+	// the default locale is used for the messages that are not translated, so it needs all of them
+	for key := range templates.html {
+		if _, ok := templates.html[emailTemplateKey{locale: DefaultLocale, name: key.name}]; !ok {
+			return nil, fmt.Errorf("email template %s has no %s version", key.name, DefaultLocale)
+		}
+	}
+	if len(templates.html) == 0 {
+		return nil, errors.New("no email templates found")
+	}
+
+	return templates, nil
+}
+
+// Render renders the message name in locale, or in the default locale when it is not translated
+func (templates *EmailTemplates) Render(name string, locale string, data any) (EmailContent, error) {
+	key := emailTemplateKey{locale: locale, name: name}
+	if _, ok := templates.html[key]; !ok {
+		key.locale = DefaultLocale
+	}
+	html, ok := templates.html[key]
+	if !ok {
+		return EmailContent{}, fmt.Errorf("unknown email template: %s", name)
+	}
+	text := templates.text[key]
+
+	var subject, textPart, htmlPart strings.Builder
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return EmailContent{}, fmt.Errorf("failed to render email subject: %w", err)
+	}
+	if err := text.Execute(&textPart, data); err != nil {
+		return EmailContent{}, fmt.Errorf("failed to render email text: %w", err)
+	}
+	if err := html.Execute(&htmlPart, data); err != nil {
+		return EmailContent{}, fmt.Errorf("failed to render email html: %w", err)
+	}
+
+	return EmailContent{
+		// a subject is a single line
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		HTML:    htmlPart.String(),
+		Text:    strings.TrimSpace(textPart.String()) + "\n",
+	}, nil
+}
+
+// URL returns the public address of path, for the links in the emails
+func (templates *EmailTemplates) URL(path string, query url.Values) string {
+	link := templates.baseURL.JoinPath(path)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// PageURL returns the link to a page of the bank, carrying params in its fragment. Browsers never
+// send the fragment to the server, so the secrets in params stay out of its logs.
+func (templates *EmailTemplates) PageURL(path string, params url.Values) string {
+	link := templates.baseURL.JoinPath(path)
+	link.Fragment = params.Encode()
+	return link.String()
+}
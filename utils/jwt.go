@@ -1,52 +1,103 @@
 package utils
 
 import (
+	"crypto/ed25519"
 	"errors"
-	// "fmt"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
-func GenerateToken(username string, duration time.Duration, secret string) (uuid.UUID, string, pgtype.Timestamptz, error) {
-	tokenId := uuid.New()
-	expirationTime := pgtype.Timestamptz{
-		Time:  time.Now().Add(duration),
-		Valid: true,
+const minSecretKeySize = 32
+
+// JWTMaker signs tokens as JSON web tokens
+type JWTMaker struct {
+	kid       string
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+	publicKey ed25519.PublicKey
+}
+
+// NewHS256Maker creates a maker signing with a secret shared by everyone who verifies the tokens
+func NewHS256Maker(kid string, secret string) (*JWTMaker, error) {
+	if len(secret) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid secret: must be at least %d characters", minSecretKeySize)
+	}
+	return &JWTMaker{
+		kid:       kid,
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}, nil
+}
+
+// NewEdDSAMaker creates a maker signing with an ed25519 key, whose tokens can be verified with the public key alone
+func NewEdDSAMaker(kid string, privateKey ed25519.PrivateKey) *JWTMaker {
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	return &JWTMaker{
+		kid:       kid,
+		method:    jwt.SigningMethodEdDSA,
+		signKey:   privateKey,
+		verifyKey: publicKey,
+		publicKey: publicKey,
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"ID":       tokenId,
-		"username": username,
-		"exp":      expirationTime.Time.Unix(),
+}
+
+func (maker *JWTMaker) CreateToken(username string, role string, sessionID uuid.UUID, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	payload := NewPayload(username, role, sessionID, tokenType, duration)
+	token := jwt.NewWithClaims(maker.method, jwt.MapClaims{
+		"ID":         payload.ID.String(),
+		"session_id": payload.SessionID.String(),
+		"token_type": string(payload.Type),
+		"username":   payload.Username,
+		"role":       payload.Role,
+		"iat":        payload.IssuedAt.Unix(),
+		"exp":        payload.ExpiredAt.Unix(),
 	})
-	accessToken, err := token.SignedString([]byte(secret))
-	return tokenId, accessToken, expirationTime, err
+	if maker.kid != "" {
+		token.Header["kid"] = maker.kid
+	}
+	signed, err := token.SignedString(maker.signKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, payload, nil
 }
 
-func VerifyToken(token, secret string) (uuid.UUID, string, error) {
-	parsedToken, err := jwt.Parse(token, func(tkn *jwt.Token) (any, error) {
-		_, isValidSigningMethod := tkn.Method.(*jwt.SigningMethodHMAC)
-		if !isValidSigningMethod {
-			return nil, errors.New("unexpected signing method")
+func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(tkn *jwt.Token) (any, error) {
+		return maker.verifyKey, nil
+	}, jwt.WithValidMethods([]string{maker.method.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
 		}
-		return []byte(secret), nil
-	})
-	// fmt.Println("parsedToken", parsedToken)
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := payloadFromClaims(claims, "ID")
 	if err != nil {
-		return uuid.UUID{}, "", errors.New("could not parse token")
+		return nil, err
 	}
-	isTokenValid := parsedToken.Valid
-	if !isTokenValid {
-		return uuid.UUID{}, "", errors.New("invalid token")
+	expiredAt, err := claims.GetExpirationTime()
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	payload.ExpiredAt = expiredAt.Time
+	// tokens issued before the iat claim was added leave IssuedAt zero
+	if issuedAt, err := claims.GetIssuedAt(); err == nil && issuedAt != nil {
+		payload.IssuedAt = issuedAt.Time
 	}
-	claims, ok := parsedToken.Claims.(jwt.MapClaims)
-	if !ok {
-		return uuid.UUID{}, "", errors.New("invalid token claims")
+	return payload, nil
+}
+
+func (maker *JWTMaker) PublicKeys() []JSONWebKey {
+	if maker.publicKey == nil {
+		return nil
 	}
-	// fmt.Println("Claims", claims)
-	id := claims["ID"].(string)
-	username := claims["username"].(string)
-	return uuid.MustParse(id), username, nil
+	return []JSONWebKey{ed25519JSONWebKey(maker.kid, maker.publicKey, maker.method.Alg())}
 }
@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// KeyringRefreshInterval is how long a keyring uses its keys before loading them again,
+	// so that a rotation made on another server is picked up
+	KeyringRefreshInterval = time.Minute
+	// keyringUnknownKidReloadInterval limits how often tokens with an unknown kid make the keyring reload
+	keyringUnknownKidReloadInterval = time.Second
+)
+
+// Keyring is a TokenMaker holding several signing keys. The newest key that is not retiring signs
+// new tokens, and every key verifies the tokens carrying its kid until it retires.
+type Keyring struct {
+	algorithm string
+	legacyKid string
+	load      func() ([]SigningKey, error)
+
+	reloading sync.Mutex
+	mu        sync.RWMutex
+	active    TokenMaker
+	kids      []string
+	keys      map[string]keyringKey
+	loadedAt  time.Time
+	// unknownKidAt is when a token with an unknown kid last made the keyring reload
+	unknownKidAt time.Time
+}
+
+type keyringKey struct {
+	maker     TokenMaker
+	retiresAt time.Time
+}
+
+// NewKeyring creates a keyring for the keys of algorithm returned by load, newest first.
+// Tokens without a kid were issued before keys had ids, and are verified with the key legacyKid.
+func NewKeyring(algorithm string, legacyKid string, load func() ([]SigningKey, error)) (*Keyring, error) {
+	keyring := &Keyring{
+		algorithm: algorithm,
+		legacyKid: legacyKid,
+		load:      load,
+	}
+	if err := keyring.Reload(); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// Algorithm returns the algorithm of the keys
+func (keyring *Keyring) Algorithm() string {
+	return keyring.algorithm
+}
+
+// Reload loads the keys again
+func (keyring *Keyring) Reload() error {
+	signingKeys, err := keyring.load()
+	if err != nil {
+		return err
+	}
+
+	var active TokenMaker
+	var kids []string
+	keys := make(map[string]keyringKey, len(signingKeys))
+	for _, signingKey := range signingKeys {
+		if signingKey.Algorithm != keyring.algorithm {
+			continue
+		}
+		maker, err := NewSigningKeyMaker(signingKey)
+		if err != nil {
+			return err
+		}
+		kids = append(kids, signingKey.Kid)
+		keys[signingKey.Kid] = keyringKey{maker: maker, retiresAt: signingKey.RetiresAt}
+		if active == nil && signingKey.RetiresAt.IsZero() {
+			active = maker
+		}
+	}
+	if active == nil {
+		return errors.New("keyring has no active signing key")
+	}
+
+	keyring.mu.Lock()
+	defer keyring.mu.Unlock()
+	keyring.active = active
+	keyring.kids = kids
+	keyring.keys = keys
+	keyring.loadedAt = time.Now()
+	return nil
+}
+
+// refresh reloads the keys once they are older than KeyringRefreshInterval. Failing to load keeps
+// the current keys, and requests arriving while another one reloads use them too.
+func (keyring *Keyring) refresh() {
+	keyring.mu.RLock()
+	stale := time.Since(keyring.loadedAt) >= KeyringRefreshInterval
+	keyring.mu.RUnlock()
+	if stale && keyring.reloading.TryLock() {
+		defer keyring.reloading.Unlock()
+		_ = keyring.Reload()
+	}
+}
+
+// refreshUnknownKid reloads the keys for a token whose kid is not known, which may have been
+// added by a rotation on another server. It waits for a reload already running.
+func (keyring *Keyring) refreshUnknownKid() {
+	keyring.reloading.Lock()
+	defer keyring.reloading.Unlock()
+	if time.Since(keyring.unknownKidAt) < keyringUnknownKidReloadInterval {
+		return
+	}
+	keyring.unknownKidAt = time.Now()
+	_ = keyring.Reload()
+}
+
+func (keyring *Keyring) CreateToken(username string, role string, sessionID uuid.UUID, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	keyring.refresh()
+
+	keyring.mu.RLock()
+	active := keyring.active
+	keyring.mu.RUnlock()
+	return active.CreateToken(username, role, sessionID, tokenType, duration)
+}
+
+func (keyring *Keyring) VerifyToken(token string) (*Payload, error) {
+	keyring.refresh()
+
+	kid := tokenKeyID(token)
+	if kid == "" {
+		kid = keyring.legacyKid
+	}
+	key, ok := keyring.key(kid)
+	if !ok {
+		keyring.refreshUnknownKid()
+		key, ok = keyring.key(kid)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+	}
+	if !key.retiresAt.IsZero() && time.Now().After(key.retiresAt) {
+		return nil, ErrInvalidToken
+	}
+	return key.maker.VerifyToken(token)
+}
+
+func (keyring *Keyring) key(kid string) (keyringKey, bool) {
+	keyring.mu.RLock()
+	defer keyring.mu.RUnlock()
+	key, ok := keyring.keys[kid]
+	return key, ok
+}
+
+func (keyring *Keyring) PublicKeys() []JSONWebKey {
+	keyring.refresh()
+
+	keyring.mu.RLock()
+	defer keyring.mu.RUnlock()
+	publicKeys := []JSONWebKey{}
+	for _, kid := range keyring.kids {
+		publicKeys = append(publicKeys, keyring.keys[kid].maker.PublicKeys()...)
+	}
+	if len(publicKeys) == 0 {
+		return nil
+	}
+	return publicKeys
+}
+
+// tokenKeyID returns the kid a token says it is signed with, before it is verified.
+// It is read from the header of a JWT or the footer of a PASETO.
+func tokenKeyID(token string) string {
+	var encoded string
+	if body, found := strings.CutPrefix(token, pasetoV4PublicHeader); found {
+		_, encoded, _ = strings.Cut(body, ".")
+	} else {
+		encoded, _, _ = strings.Cut(token, ".")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ""
+	}
+	var header struct {
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return ""
+	}
+	return header.Kid
+}
@@ -0,0 +1,17 @@
+package utils
+
+// the languages emails are sent in
+const (
+	EnglishLocale = "en"
+	SpanishLocale = "es"
+
+	DefaultLocale = EnglishLocale
+)
+
+func IsSupportedLocale(locale string) bool {
+	switch locale {
+	case EnglishLocale, SpanishLocale:
+		return true
+	}
+	return false
+}
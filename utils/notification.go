@@ -0,0 +1,17 @@
+package utils
+
+// the channels notifications are sent through, users can opt out of each of them
+const (
+	EmailChannel = "email"
+)
+
+// NotificationChannels lists every supported channel
+var NotificationChannels = []string{EmailChannel}
+
+func IsSupportedNotificationChannel(channel string) bool {
+	switch channel {
+	case EmailChannel:
+		return true
+	}
+	return false
+}
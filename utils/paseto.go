@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// pasetoV4PublicHeader starts every v4.public token (https://github.com/paseto-standard/paseto-spec/blob/master/docs/01-Protocol-Versions/Version4.md)
+const pasetoV4PublicHeader = "v4.public."
+
+// PasetoMaker signs tokens as PASETO v4.public tokens
+type PasetoMaker struct {
+	kid        string
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+func NewPasetoMaker(kid string, privateKey ed25519.PrivateKey) *PasetoMaker {
+	return &PasetoMaker{
+		kid:        kid,
+		privateKey: privateKey,
+		publicKey:  privateKey.Public().(ed25519.PublicKey),
+	}
+}
+
+// pasetoFooter is the footer of the tokens, which names the key they are signed with
+type pasetoFooter struct {
+	Kid string `json:"kid"`
+}
+
+// pasetoClaims uses the registered claim names of the PASETO spec, with the times in RFC 3339
+type pasetoClaims struct {
+	ID        string `json:"jti"`
+	SessionID string `json:"session_id"`
+	Type      string `json:"token_type"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	IssuedAt  string `json:"iat"`
+	ExpiredAt string `json:"exp"`
+}
+
+func (maker *PasetoMaker) CreateToken(username string, role string, sessionID uuid.UUID, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	payload := NewPayload(username, role, sessionID, tokenType, duration)
+	message, err := json.Marshal(pasetoClaims{
+		ID:        payload.ID.String(),
+		SessionID: payload.SessionID.String(),
+		Type:      string(payload.Type),
+		Username:  payload.Username,
+		Role:      payload.Role,
+		IssuedAt:  payload.IssuedAt.UTC().Format(time.RFC3339),
+		ExpiredAt: payload.ExpiredAt.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	var footer []byte
+	if maker.kid != "" {
+		footer, err = json.Marshal(pasetoFooter{Kid: maker.kid})
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	signature := ed25519.Sign(maker.privateKey, pasetoPreAuthEncode([]byte(pasetoV4PublicHeader), message, footer, nil))
+	token := pasetoV4PublicHeader + base64.RawURLEncoding.EncodeToString(append(message, signature...))
+	if len(footer) > 0 {
+		token += "." + base64.RawURLEncoding.EncodeToString(footer)
+	}
+	return token, payload, nil
+}
+
+func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+	body, found := strings.CutPrefix(token, pasetoV4PublicHeader)
+	if !found {
+		return nil, ErrInvalidToken
+	}
+	// the footer is optional, and only names the key
+	var footer []byte
+	if encodedBody, encodedFooter, found := strings.Cut(body, "."); found {
+		var err error
+		footer, err = base64.RawURLEncoding.DecodeString(encodedFooter)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+		body = encodedBody
+	}
+	signed, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil || len(signed) < ed25519.SignatureSize {
+		return nil, ErrInvalidToken
+	}
+	message := signed[:len(signed)-ed25519.SignatureSize]
+	signature := signed[len(signed)-ed25519.SignatureSize:]
+	if !ed25519.Verify(maker.publicKey, pasetoPreAuthEncode([]byte(pasetoV4PublicHeader), message, footer, nil), signature) {
+		return nil, ErrInvalidToken
+	}
+
+	var claims map[string]any
+	if err := json.Unmarshal(message, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	payload, err := payloadFromClaims(claims, "jti")
+	if err != nil {
+		return nil, err
+	}
+	payload.ExpiredAt, err = pasetoTimeClaim(claims, "exp")
+	if err != nil {
+		return nil, err
+	}
+	payload.IssuedAt, err = pasetoTimeClaim(claims, "iat")
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(payload.ExpiredAt) {
+		return nil, ErrExpiredToken
+	}
+	return payload, nil
+}
+
+func (maker *PasetoMaker) PublicKeys() []JSONWebKey {
+	// JOSE has no algorithm name for PASETO, so the key is published without one
+	return []JSONWebKey{ed25519JSONWebKey(maker.kid, maker.publicKey, "")}
+}
+
+func pasetoTimeClaim(claims map[string]any, name string) (time.Time, error) {
+	value, ok := claims[name].(string)
+	if !ok {
+		return time.Time{}, ErrInvalidToken
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidToken
+	}
+	return parsed, nil
+}
+
+// pasetoPreAuthEncode is the PAE function of the PASETO spec. It encodes the pieces so
+// that no two different lists of pieces sign the same bytes.
+func pasetoPreAuthEncode(pieces ...[]byte) []byte {
+	var buf bytes.Buffer
+	pasetoLE64(&buf, len(pieces))
+	for _, piece := range pieces {
+		pasetoLE64(&buf, len(piece))
+		buf.Write(piece)
+	}
+	return buf.Bytes()
+}
+
+func pasetoLE64(buf *bytes.Buffer, n int) {
+	var le [8]byte
+	// the most significant bit is always cleared, for interoperability with languages without unsigned integers
+	binary.LittleEndian.PutUint64(le[:], uint64(n)&^(1<<63))
+	buf.Write(le[:])
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenType tells the access token of a session from its refresh token
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+// Payload contains the claims of a verified token
+type Payload struct {
+	ID        uuid.UUID
+	SessionID uuid.UUID
+	Type      TokenType
+	Username  string
+	Role      string
+	IssuedAt  time.Time
+	ExpiredAt time.Time
+}
+
+func NewPayload(username string, role string, sessionID uuid.UUID, tokenType TokenType, duration time.Duration) *Payload {
+	// the claims carry whole seconds, so the payload is truncated to match what the token verifies to
+	now := time.Now().Truncate(time.Second)
+	return &Payload{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		Type:      tokenType,
+		Username:  username,
+		Role:      role,
+		IssuedAt:  now,
+		ExpiredAt: now.Add(duration),
+	}
+}
+
+// payloadFromClaims reads the claims shared by every token format. idClaim names the claim holding the token id.
+func payloadFromClaims(claims map[string]any, idClaim string) (*Payload, error) {
+	id, ok := claims[idClaim].(string)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	tokenId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	// the refresh tokens issued before session ids were added are their own session
+	sessionId := tokenId
+	if value, ok := claims["session_id"].(string); ok {
+		sessionId, err = uuid.Parse(value)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+	}
+	// tokens issued before the type claim was added can't be told apart, so they are all rejected
+	tokenType, ok := claims["token_type"].(string)
+	if !ok || (TokenType(tokenType) != TokenTypeAccess && TokenType(tokenType) != TokenTypeRefresh) {
+		return nil, ErrInvalidToken
+	}
+	username, ok := claims["username"].(string)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	// tokens issued before roles existed carry the least privileged one
+	role, ok := claims["role"].(string)
+	if !ok {
+		role = DepositorRole
+	}
+	if !IsSupportedRole(role) {
+		return nil, ErrInvalidToken
+	}
+	return &Payload{
+		ID:        tokenId,
+		SessionID: sessionId,
+		Type:      TokenType(tokenType),
+		Username:  username,
+		Role:      role,
+	}, nil
+}
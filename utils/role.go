@@ -0,0 +1,20 @@
+package utils
+
+const (
+	DepositorRole = "depositor"
+	BankerRole    = "banker"
+	AdminRole     = "admin"
+)
+
+func IsSupportedRole(role string) bool {
+	switch role {
+	case DepositorRole, BankerRole, AdminRole:
+		return true
+	}
+	return false
+}
+
+// IsStaffRole reports whether role belongs to an employee of the bank, who can view the data of any customer
+func IsStaffRole(role string) bool {
+	return role == BankerRole || role == AdminRole
+}
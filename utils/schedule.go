@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	FrequencyOnce    = "once"
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyCron    = "cron"
+)
+
+func IsSupportedFrequency(frequency string) bool {
+	switch frequency {
+	case FrequencyOnce, FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyCron:
+		return true
+	}
+	return false
+}
+
+// ParseCronExpression parses a standard 5 field cron expression, evaluated in UTC
+func ParseCronExpression(expression string) (cron.Schedule, error) {
+	return cron.ParseStandard(expression)
+}
+
+// NextRun returns the first occurrence of a schedule starting at start that is strictly after after.
+// Daily, weekly and monthly occurrences are always counted from start, so that a late or retried
+// run doesn't shift the following ones. A one-off schedule has no occurrence after its start.
+func NextRun(frequency string, cronExpression string, start time.Time, after time.Time) (time.Time, error) {
+	var step func(n int) time.Time
+	var days int // the longest length of a step, in days
+	switch frequency {
+	case FrequencyOnce:
+		if start.After(after) {
+			return start, nil
+		}
+		return time.Time{}, fmt.Errorf("one-off schedule has no run after %s", after)
+	case FrequencyDaily:
+		step = func(n int) time.Time { return start.AddDate(0, 0, n) }
+		days = 1
+	case FrequencyWeekly:
+		step = func(n int) time.Time { return start.AddDate(0, 0, 7*n) }
+		days = 7
+	case FrequencyMonthly:
+		step = func(n int) time.Time { return addMonths(start, n) }
+		days = 31
+	case FrequencyCron:
+		schedule, err := ParseCronExpression(cronExpression)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if start.After(after) {
+			after = start.Add(-time.Second)
+		}
+		next := schedule.Next(after.UTC())
+		if next.IsZero() {
+			return time.Time{}, fmt.Errorf("cron expression %q has no run after %s", cronExpression, after)
+		}
+		return next, nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported frequency %q", frequency)
+	}
+
+	// jump close to the answer first, the step lengths vary with DST and month length
+	n := 0
+	if after.After(start) {
+		n = int(after.Sub(start)/(24*time.Hour))/days - 2
+		if n < 0 {
+			n = 0
+		}
+	}
+	for !step(n).After(after) {
+		n++
+	}
+	return step(n), nil
+}
+
+// addMonths adds n months to t, keeping its day of the month. A day the month doesn't have
+// is clamped to the month's last day, where AddDate would overflow into the next month.
+func addMonths(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	// day 0 of the month after the target month is the target month's last day
+	lastDay := time.Date(year, month+time.Month(n)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	return time.Date(year, month+time.Month(n), min(day, lastDay), hour, minute, second, t.Nanosecond(), t.Location())
+}
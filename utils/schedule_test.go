@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func TestNextRunMonthly(t *testing.T) {
+	testCases := []struct {
+		name  string
+		start time.Time
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "ClampToFebruary",
+			start: date(2025, time.January, 31),
+			after: date(2025, time.January, 31),
+			want:  date(2025, time.February, 28),
+		},
+		{
+			name:  "ClampToLeapFebruary",
+			start: date(2024, time.January, 31),
+			after: date(2024, time.January, 31),
+			want:  date(2024, time.February, 29),
+		},
+		{
+			// a clamped run doesn't move the day of the runs after it
+			name:  "BackToLastDayAfterClamp",
+			start: date(2025, time.January, 31),
+			after: date(2025, time.February, 28),
+			want:  date(2025, time.March, 31),
+		},
+		{
+			name:  "ClampToThirtyDayMonth",
+			start: date(2025, time.January, 31),
+			after: date(2025, time.March, 31),
+			want:  date(2025, time.April, 30),
+		},
+		{
+			name:  "AcrossYear",
+			start: date(2024, time.December, 31),
+			after: date(2024, time.December, 31),
+			want:  date(2025, time.January, 31),
+		},
+		{
+			name:  "LateRun",
+			start: date(2025, time.January, 31),
+			after: date(2025, time.May, 2),
+			want:  date(2025, time.May, 31),
+		},
+		{
+			name:  "BeforeStart",
+			start: date(2025, time.January, 31),
+			after: date(2025, time.January, 1),
+			want:  date(2025, time.January, 31),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NextRun(FrequencyMonthly, "", tc.start, tc.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tc.want) {
+				t.Fatalf("next run is %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	start := date(2025, time.January, 31)
+	want := []time.Time{
+		date(2025, time.January, 31),
+		date(2025, time.February, 28),
+		date(2025, time.March, 31),
+		date(2025, time.April, 30),
+		date(2025, time.May, 31),
+	}
+	for n, want := range want {
+		if got := addMonths(start, n); !got.Equal(want) {
+			t.Fatalf("%d months after %s is %s, want %s", n, start, got, want)
+		}
+	}
+	if got, want := addMonths(start, -2), date(2024, time.November, 30); !got.Equal(want) {
+		t.Fatalf("2 months before %s is %s, want %s", start, got, want)
+	}
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+const signingKeyEncryptionKeySize = 32
+
+var ErrInvalidSealedKey = errors.New("cannot decrypt signing key")
+
+// SigningKeyCipher encrypts the signing keys kept in the database with AES-256-GCM, so that
+// reading the database alone is not enough to forge tokens
+type SigningKeyCipher struct {
+	aead cipher.AEAD
+}
+
+// NewSigningKeyCipher creates a cipher for a base64 encoded 32 byte key
+func NewSigningKeyCipher(encodedKey string) (*SigningKeyCipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key encryption key: %w", err)
+	}
+	if len(key) != signingKeyEncryptionKeySize {
+		return nil, fmt.Errorf("invalid signing key encryption key: must be %d bytes", signingKeyEncryptionKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKeyCipher{aead: aead}, nil
+}
+
+// Seal encrypts the key of kid. The kid is authenticated along with the key, so that
+// a sealed key copied to another row does not open.
+func (keyCipher *SigningKeyCipher) Seal(kid string, key []byte) ([]byte, error) {
+	nonce := make([]byte, keyCipher.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return keyCipher.aead.Seal(nonce, nonce, key, []byte(kid)), nil
+}
+
+// Open decrypts a key sealed for kid
+func (keyCipher *SigningKeyCipher) Open(kid string, sealed []byte) ([]byte, error) {
+	nonceSize := keyCipher.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, ErrInvalidSealedKey
+	}
+	key, err := keyCipher.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(kid))
+	if err != nil {
+		return nil, ErrInvalidSealedKey
+	}
+	return key, nil
+}
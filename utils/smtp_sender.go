@@ -0,0 +1,251 @@
+package utils
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jordan-wright/email"
+)
+
+const (
+	// SMTPTLSModeNone never uses TLS, for relays on a trusted network and local test servers
+	SMTPTLSModeNone = "none"
+	// SMTPTLSModeStartTLS connects without TLS and requires the server to upgrade with STARTTLS
+	SMTPTLSModeStartTLS = "starttls"
+	// SMTPTLSModeTLS connects with TLS from the start
+	SMTPTLSModeTLS = "tls"
+
+	SMTPAuthNone    = "none"
+	SMTPAuthPlain   = "plain"
+	SMTPAuthLogin   = "login"
+	SMTPAuthCRAMMD5 = "cram-md5"
+
+	smtpDialTimeout = 10 * time.Second
+	smtpSendTimeout = time.Minute
+)
+
+// SMTPConfig holds the SMTP server an SMTPSender sends emails through
+type SMTPConfig struct {
+	Host string
+	// Port defaults to the usual port of TLSMode
+	Port     int
+	TLSMode  string
+	Auth     string
+	Username string
+	Password string
+	// FromName and FromAddress are the sender of the emails
+	FromName    string
+	FromAddress string
+}
+
+// SMTPSender sends emails through any SMTP server
+type SMTPSender struct {
+	config SMTPConfig
+}
+
+func NewSMTPSender(config SMTPConfig) (EmailSender, error) {
+	if config.Host == "" {
+		return nil, errors.New("smtp host is not set")
+	}
+	if config.FromAddress == "" {
+		return nil, errors.New("smtp from address is not set")
+	}
+
+	if config.TLSMode == "" {
+		config.TLSMode = SMTPTLSModeStartTLS
+	}
+	defaultPort := map[string]int{
+		SMTPTLSModeNone:     25,
+		SMTPTLSModeStartTLS: 587,
+		SMTPTLSModeTLS:      465,
+	}
+	port, ok := defaultPort[config.TLSMode]
+	if !ok {
+		return nil, fmt.Errorf("unsupported smtp tls mode: %s", config.TLSMode)
+	}
+	if config.Port == 0 {
+		config.Port = port
+	}
+
+	if config.Auth == "" {
+		config.Auth = SMTPAuthNone
+	}
+	switch config.Auth {
+	case SMTPAuthNone:
+	case SMTPAuthPlain, SMTPAuthLogin, SMTPAuthCRAMMD5:
+		if config.Username == "" {
+			return nil, fmt.Errorf("smtp username is required by %s auth", config.Auth)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported smtp auth: %s", config.Auth)
+	}
+
+	return &SMTPSender{
+		config: config,
+	}, nil
+}
+
+func (sender *SMTPSender) SendEmail(content EmailContent, to []string, cc []string, bcc []string, attachFiles []string) error {
+	e := email.NewEmail()
+	e.From = sender.config.FromAddress
+	if sender.config.FromName != "" {
+		e.From = fmt.Sprintf("%s <%s>", sender.config.FromName, sender.config.FromAddress)
+	}
+	e.Subject = content.Subject
+	// with both parts set, the email is sent as multipart/alternative
+	e.HTML = []byte(content.HTML)
+	e.Text = []byte(content.Text)
+	e.To = to
+	e.Cc = cc
+	e.Bcc = bcc
+
+	for _, f := range attachFiles {
+		_, err := e.AttachFile(f)
+		if err != nil {
+			return fmt.Errorf("failed to attach file %s: %w", f, err)
+		}
+	}
+
+	recipients := make([]string, 0, len(to)+len(cc)+len(bcc))
+	for _, recipient := range slices.Concat(to, cc, bcc) {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %s: %w", recipient, err)
+		}
+		recipients = append(recipients, address.Address)
+	}
+	message, err := e.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	return sender.send(recipients, message)
+}
+
+func (sender *SMTPSender) send(recipients []string, message []byte) error {
+	addr := net.JoinHostPort(sender.config.Host, strconv.Itoa(sender.config.Port))
+	tlsConfig := &tls.Config{
+		ServerName: sender.config.Host,
+		MinVersion: tls.VersionTLS12,
+	}
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+
+	var conn net.Conn
+	var err error
+	if sender.config.TLSMode == SMTPTLSModeTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpSendTimeout))
+
+	client, err := smtp.NewClient(conn, sender.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to greet smtp server: %w", err)
+	}
+	defer client.Close()
+
+	if sender.config.TLSMode == SMTPTLSModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	if auth := sender.auth(); auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support AUTH")
+		}
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(sender.config.FromAddress); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// auth returns the smtp.Auth of the configured mechanism. The mechanisms sending the password refuse
+// connections without TLS, unless the server is on localhost.
+func (sender *SMTPSender) auth() smtp.Auth {
+	switch sender.config.Auth {
+	case SMTPAuthPlain:
+		return smtp.PlainAuth("", sender.config.Username, sender.config.Password, sender.config.Host)
+	case SMTPAuthLogin:
+		return &loginAuth{
+			username: sender.config.Username,
+			password: sender.config.Password,
+			host:     sender.config.Host,
+		}
+	case SMTPAuthCRAMMD5:
+		return smtp.CRAMMD5Auth(sender.config.Username, sender.config.Password)
+	default:
+		return nil
+	}
+}
+
+// loginAuth implements the LOGIN mechanism, which net/smtp does not provide but many relays require
+type loginAuth struct {
+	username string
+	password string
+	host     string
+}
+
+func (auth *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != auth.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (auth *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(auth.username), nil
+	case "password:":
+		return []byte(auth.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge: %s", fromServer)
+	}
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
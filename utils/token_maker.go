@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TokenAlgorithmHS256    = "HS256"
+	TokenAlgorithmEdDSA    = "EdDSA"
+	TokenAlgorithmPasetoV4 = "PASETO_V4"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// TokenMaker creates and verifies the access and refresh tokens of login sessions
+type TokenMaker interface {
+	// CreateToken creates a token for a login session. The access and refresh tokens of a session carry the same session id,
+	// and are told apart by their type.
+	CreateToken(username string, role string, sessionID uuid.UUID, tokenType TokenType, duration time.Duration) (string, *Payload, error)
+	// VerifyToken checks the token and returns the payload it carries
+	VerifyToken(token string) (*Payload, error)
+	// PublicKeys returns the keys other services verify tokens with, or nothing for a shared secret
+	PublicKeys() []JSONWebKey
+}
+
+// JSONWebKey is a public key in the JWK format (RFC 7517)
+type JSONWebKey struct {
+	Kid string `json:"kid,omitempty"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+}
+
+// SigningKey is a key tokens are signed with. Key holds the secret of HS256, or the ed25519 seed of the other algorithms.
+type SigningKey struct {
+	Kid       string
+	Algorithm string
+	Key       []byte
+	// RetiresAt is when the key stops verifying tokens, or zero while it is still signing them
+	RetiresAt time.Time
+}
+
+// NewTokenMaker creates the token maker for the key set in config
+func NewTokenMaker(config Config) (TokenMaker, error) {
+	key, err := ConfigSigningKey(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigningKeyMaker(key)
+}
+
+// ConfigSigningKey returns the key set in config, for the algorithm set in config
+func ConfigSigningKey(config Config) (SigningKey, error) {
+	key := SigningKey{
+		Algorithm: config.TokenAlgorithm,
+	}
+	switch config.TokenAlgorithm {
+	case "", TokenAlgorithmHS256:
+		key.Algorithm = TokenAlgorithmHS256
+		key.Key = []byte(config.Secret)
+	case TokenAlgorithmEdDSA, TokenAlgorithmPasetoV4:
+		privateKey, err := ParseEd25519PrivateKey(config.TokenPrivateKey)
+		if err != nil {
+			return SigningKey{}, err
+		}
+		key.Key = privateKey.Seed()
+	default:
+		return SigningKey{}, fmt.Errorf("unsupported token algorithm: %s", config.TokenAlgorithm)
+	}
+	key.Kid = signingKeyID(key.Algorithm, key.Key)
+	return key, nil
+}
+
+// GenerateSigningKey creates a random key for algorithm
+func GenerateSigningKey(algorithm string) (SigningKey, error) {
+	random := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(random); err != nil {
+		return SigningKey{}, err
+	}
+
+	var key []byte
+	switch algorithm {
+	case TokenAlgorithmHS256:
+		// the secret is used as a string, so it is kept printable
+		key = []byte(base64.RawURLEncoding.EncodeToString(random))
+	case TokenAlgorithmEdDSA, TokenAlgorithmPasetoV4:
+		key = random
+	default:
+		return SigningKey{}, fmt.Errorf("unsupported token algorithm: %s", algorithm)
+	}
+
+	return SigningKey{
+		Kid:       signingKeyID(algorithm, key),
+		Algorithm: algorithm,
+		Key:       key,
+	}, nil
+}
+
+// NewSigningKeyMaker creates the token maker signing with key
+func NewSigningKeyMaker(key SigningKey) (TokenMaker, error) {
+	switch key.Algorithm {
+	case TokenAlgorithmHS256:
+		return NewHS256Maker(key.Kid, string(key.Key))
+	case TokenAlgorithmEdDSA, TokenAlgorithmPasetoV4:
+		if len(key.Key) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid signing key %s: must be %d bytes", key.Kid, ed25519.SeedSize)
+		}
+		privateKey := ed25519.NewKeyFromSeed(key.Key)
+		if key.Algorithm == TokenAlgorithmEdDSA {
+			return NewEdDSAMaker(key.Kid, privateKey), nil
+		}
+		return NewPasetoMaker(key.Kid, privateKey), nil
+	}
+	return nil, fmt.Errorf("unsupported token algorithm: %s", key.Algorithm)
+}
+
+// signingKeyID derives the kid of a key from the key, so every server computes the same kid for the configured key.
+// Asymmetric keys are identified by their public key.
+func signingKeyID(algorithm string, key []byte) string {
+	if algorithm != TokenAlgorithmHS256 && len(key) == ed25519.SeedSize {
+		key = ed25519.NewKeyFromSeed(key).Public().(ed25519.PublicKey)
+	}
+	sum := sha256.Sum256(key)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// ParseEd25519PrivateKey decodes a base64 encoded ed25519 seed or private key
+func ParseEd25519PrivateKey(value string) (ed25519.PrivateKey, error) {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token private key: %w", err)
+	}
+	switch len(key) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(key), nil
+	case ed25519.PrivateKeySize:
+		privateKey := ed25519.PrivateKey(key)
+		// the second half of a private key is its public key
+		if !ed25519.NewKeyFromSeed(privateKey.Seed()).Equal(privateKey) {
+			return nil, errors.New("invalid token private key: public key does not match")
+		}
+		return privateKey, nil
+	}
+	return nil, fmt.Errorf("invalid token private key: must be %d or %d bytes", ed25519.SeedSize, ed25519.PrivateKeySize)
+}
+
+func ed25519JSONWebKey(kid string, publicKey ed25519.PublicKey, alg string) JSONWebKey {
+	return JSONWebKey{
+		Kid: kid,
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(publicKey),
+		Use: "sig",
+		Alg: alg,
+	}
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	TOTPIssuer = "Go-Bank"
+	// RecoveryCodeCount is how many recovery codes are issued with a totp secret
+	RecoveryCodeCount = 10
+
+	totpPeriod = 30
+	// totpSkew accepts the codes of the previous and next periods, for clocks that drift
+	totpSkew = 1
+)
+
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijkmnpqrstuvwxyz23456789").WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret creates a totp secret for account, and the otpauth URI authenticator apps enroll it with
+func GenerateTOTPSecret(account string) (secret string, uri string, err error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      TOTPIssuer,
+		AccountName: account,
+		Period:      totpPeriod,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return key.Secret(), key.URL(), nil
+}
+
+// ValidateTOTP checks a code against secret, and returns the time step it belongs to.
+// Every step is only accepted once, so callers must remember the last step used.
+func ValidateTOTP(secret string, code string, now time.Time) (int64, bool) {
+	step := now.Unix() / totpPeriod
+	for skew := int64(-totpSkew); skew <= totpSkew; skew++ {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix((step+skew)*totpPeriod, 0), totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+		if expected == code {
+			return step + skew, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes creates the one-time codes that replace a totp code when the authenticator is lost
+func GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	for len(codes) < RecoveryCodeCount {
+		random := make([]byte, 7)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+		code := recoveryCodeEncoding.EncodeToString(random)[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode hashes a recovery code for storage. The codes are random, so a plain hash is enough.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,12 +3,17 @@ package utils
 import (
 	"fmt"
 	"net/mail"
+	"net/netip"
+	"net/url"
 	"regexp"
+	"strings"
+	"time"
 )
 
 var (
 	isValidUsername = regexp.MustCompile(`^[a-z0-9_]+$`).MatchString
 	isValidFullName = regexp.MustCompile(`^[a-zA-Z\s]+$`).MatchString
+	isValidTOTPCode = regexp.MustCompile(`^[0-9]{6}$`).MatchString
 )
 
 func ValidateString(value string, minLength int, maxLength int) error {
@@ -53,6 +58,50 @@ func ValidateEmail(value string) error {
 	return nil
 }
 
+func ValidateLocale(value string) error {
+	if !IsSupportedLocale(value) {
+		return fmt.Errorf("unsupported locale")
+	}
+	return nil
+}
+
+func ValidateNotificationChannel(value string) error {
+	if !IsSupportedNotificationChannel(value) {
+		return fmt.Errorf("unsupported notification channel")
+	}
+	return nil
+}
+
+// ValidateWebhookURL accepts absolute http and https URLs
+func ValidateWebhookURL(value string) error {
+	if err := ValidateString(value, 8, 2048); err != nil {
+		return err
+	}
+	webhookURL, err := url.Parse(value)
+	if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		return fmt.Errorf("is not a valid http or https url")
+	}
+	if webhookURL.User != nil {
+		return fmt.Errorf("must not contain credentials")
+	}
+	// names are checked again once resolved, when a delivery connects
+	host := webhookURL.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicWebhookAddress(addr) {
+		return fmt.Errorf("must not point to a private address")
+	}
+	if host = strings.ToLower(strings.TrimSuffix(host, ".")); host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("must not point to a private address")
+	}
+	return nil
+}
+
+func ValidateWebhookEvent(value string) error {
+	if !IsSupportedWebhookEvent(value) {
+		return fmt.Errorf("unsupported webhook event")
+	}
+	return nil
+}
+
 func ValidateEmailId(value int64) error {
 	if value <= 0 {
 		return fmt.Errorf("must be a positive integer")
@@ -63,3 +112,88 @@ func ValidateEmailId(value int64) error {
 func ValidateSecretCode(value string) error {
 	return ValidateString(value, 32, 128)
 }
+
+func ValidateId(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+func ValidateCurrency(value string) error {
+	if !IsSupportedCurrency(value) {
+		return fmt.Errorf("unsupported currency")
+	}
+	return nil
+}
+
+func ValidatePageId(value int32) error {
+	if value < 1 {
+		return fmt.Errorf("must be greater than or equal to 1")
+	}
+	return nil
+}
+
+func ValidatePageSize(value int32) error {
+	if value < 5 || value > 10 {
+		return fmt.Errorf("must be from 5-10")
+	}
+	return nil
+}
+
+func ValidateAmount(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+func ValidateIdempotencyKey(value string) error {
+	return ValidateString(value, 1, 255)
+}
+
+func ValidateStatementPeriod(start time.Time, end time.Time, maxPeriod time.Duration) error {
+	if !end.After(start) {
+		return fmt.Errorf("must be after start_time")
+	}
+	if end.Sub(start) > maxPeriod {
+		return fmt.Errorf("period must not be longer than %d days", int(maxPeriod.Hours()/24))
+	}
+	return nil
+}
+
+func ValidateFrequency(value string) error {
+	if !IsSupportedFrequency(value) {
+		return fmt.Errorf("must be one of %s, %s, %s, %s or %s", FrequencyOnce, FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyCron)
+	}
+	return nil
+}
+
+func ValidateCronExpression(value string) error {
+	if _, err := ParseCronExpression(value); err != nil {
+		return fmt.Errorf("invalid cron expression: %s", err)
+	}
+	return nil
+}
+
+func ValidateOverdraftLimit(value int64) error {
+	if value < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	return nil
+}
+
+func ValidateTOTPCode(value string) error {
+	if !isValidTOTPCode(value) {
+		return fmt.Errorf("must contain 6 digits")
+	}
+	return nil
+}
+
+// ValidateTwoFactorCode accepts a totp code or a recovery code
+func ValidateTwoFactorCode(value string) error {
+	if isValidTOTPCode(value) {
+		return nil
+	}
+	return ValidateString(value, 10, 11)
+}
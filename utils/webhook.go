@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the events webhook endpoints can subscribe to
+const (
+	WebhookEventAccountCreated    = "account.created"
+	WebhookEventTransferCreated   = "transfer.created"
+	WebhookEventUserEmailVerified = "user.email_verified"
+)
+
+// the headers of a webhook delivery
+const (
+	WebhookEventHeader     = "X-GoBank-Event"
+	WebhookDeliveryHeader  = "X-GoBank-Delivery"
+	WebhookSignatureHeader = "X-GoBank-Signature"
+)
+
+// webhookSecretPrefix tells webhook secrets apart from the other credentials of the bank
+const webhookSecretPrefix = "whsec_"
+
+var (
+	ErrInvalidWebhookSignature  = errors.New("invalid webhook signature")
+	ErrWebhookAddressNotAllowed = errors.New("webhook address is not allowed")
+)
+
+// nonPublicPrefixes are the ranges that are not reachable on the internet, on top of the
+// loopback, private, link-local and multicast ones netip.Addr reports
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	// carrier-grade NAT, which also holds the metadata service of some clouds
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	// NAT64 and 6to4 addresses embed an IPv4 address, which may be an internal one
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+// IsPublicWebhookAddress reports whether webhooks may be delivered to addr. Deliveries to the
+// internal network of the bank, its cloud metadata service included, are never allowed.
+func IsPublicWebhookAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func IsSupportedWebhookEvent(eventType string) bool {
+	switch eventType {
+	case WebhookEventAccountCreated, WebhookEventTransferCreated, WebhookEventUserEmailVerified:
+		return true
+	}
+	return false
+}
+
+// GenerateWebhookSecret creates the secret the deliveries to an endpoint are signed with
+func GenerateWebhookSecret() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// SignWebhookPayload returns the signature header of a delivery made at timestamp: "t=<unix time>,v1=<signature>",
+// where the signature is the hex HMAC-SHA256 of "<unix time>.<body>" keyed with the secret of the endpoint.
+// The signed timestamp lets receivers reject old deliveries that are replayed.
+func SignWebhookPayload(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", unix, webhookSignature(secret, unix, body))
+}
+
+// VerifyWebhookSignature checks a signature header made by SignWebhookPayload, and that it was made within tolerance of now
+func VerifyWebhookSignature(secret string, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var unix, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	timestamp, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || signature == "" {
+		return ErrInvalidWebhookSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(webhookSignature(secret, unix, body))) {
+		return ErrInvalidWebhookSignature
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp is outside the tolerance", ErrInvalidWebhookSignature)
+	}
+	return nil
+}
+
+func webhookSignature(secret string, unix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendAccountLocked = "task:send_account_locked"
+
+type PayloadSendAccountLocked struct {
+	Username    string    `json:"username"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendAccountLocked(ctx context.Context, payload *PayloadSendAccountLocked, opts ...asynq.Option) error {
+	return distributeTask(ctx, distributor, TaskSendAccountLocked, payload, opts...)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendAccountLocked(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendAccountLocked
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// send email to user
+	err = processor.sendEmail("account_locked", user, user.Email, struct {
+		FullName    string
+		LockedUntil string
+	}{
+		FullName:    user.Fullname,
+		LockedUntil: payload.LockedUntil.UTC().Format(time.RFC1123),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send account locked email: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Str("email", user.Email).Msg("processed task")
+	return nil
+}
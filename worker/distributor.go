@@ -2,12 +2,21 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
 )
 
 type TaskDistributor interface {
+	DistributeTask(ctx context.Context, task *asynq.Task, opts ...asynq.Option) error
 	DistributeTaskSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opts ...asynq.Option) error
+	DistributeTaskSendPasswordReset(ctx context.Context, payload *PayloadSendPasswordReset, opts ...asynq.Option) error
+	DistributeTaskSendAccountLocked(ctx context.Context, payload *PayloadSendAccountLocked, opts ...asynq.Option) error
+	DistributeTaskSendEmailChanged(ctx context.Context, payload *PayloadSendEmailChanged, opts ...asynq.Option) error
+	DistributeTaskSendEmailChangeRequested(ctx context.Context, payload *PayloadSendEmailChangeRequested, opts ...asynq.Option) error
+	DistributeTaskSendTransferNotification(ctx context.Context, payload *PayloadSendTransferNotification, opts ...asynq.Option) error
 }
 
 type RedisTaskDistributor struct {
@@ -19,4 +28,25 @@ func NewRedisTaskDistributor(redisOpt asynq.RedisClientOpt) TaskDistributor {
 	return &RedisTaskDistributor{
 		client: client,
 	}
-}
\ No newline at end of file
+}
+
+// DistributeTask enqueues a task that has already been built, such as one relayed from the outbox
+func (distributor *RedisTaskDistributor) DistributeTask(ctx context.Context, task *asynq.Task, opts ...asynq.Option) error {
+	info, err := distributor.client.EnqueueContext(ctx, task, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// distributeTask marshals payload into a task of taskType, and distributes it through distributor
+func distributeTask(ctx context.Context, distributor TaskDistributor, taskType string, payload any, opts ...asynq.Option) error {
+	json_payload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	return distributor.DistributeTask(ctx, asynq.NewTask(taskType, json_payload), opts...)
+}
@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TaskSendEmailChanged         = "task:send_email_changed"
+	TaskSendEmailChangeRequested = "task:send_email_change_requested"
+)
+
+type PayloadSendEmailChanged struct {
+	Username string `json:"username"`
+	// PreviousEmail is the email the notice is sent to, the user no longer has it
+	PreviousEmail string `json:"previous_email"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendEmailChanged(ctx context.Context, payload *PayloadSendEmailChanged, opts ...asynq.Option) error {
+	return distributeTask(ctx, distributor, TaskSendEmailChanged, payload, opts...)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendEmailChanged(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendEmailChanged
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// send email to user
+	err = processor.sendEmail("email_changed", user, payload.PreviousEmail, struct {
+		FullName string
+	}{
+		FullName: user.Fullname,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send email changed notice: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Str("email", payload.PreviousEmail).Msg("processed task")
+	return nil
+}
+
+type PayloadSendEmailChangeRequested struct {
+	Username string `json:"username"`
+	// Email is the current email of the user, which the notice is sent to
+	Email    string `json:"email"`
+	NewEmail string `json:"new_email"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendEmailChangeRequested(ctx context.Context, payload *PayloadSendEmailChangeRequested, opts ...asynq.Option) error {
+	return distributeTask(ctx, distributor, TaskSendEmailChangeRequested, payload, opts...)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendEmailChangeRequested(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendEmailChangeRequested
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// the notice is sent even if the change was dropped or verified since, the owner of the
+	// current email has to learn that someone asked for it
+	err = processor.sendEmail("email_change_requested", user, payload.Email, struct {
+		FullName string
+		NewEmail string
+	}{
+		FullName: user.Fullname,
+		NewEmail: payload.NewEmail,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send email change requested notice: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Str("email", payload.Email).Msg("processed task")
+	return nil
+}
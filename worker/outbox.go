@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+// outboxDefaultMaxRetry is the max retry of a task that does not set one, the same as asynq's
+const outboxDefaultMaxRetry = 25
+
+// OutboxTaskDistributor distributes tasks by writing them to the outbox through q, in the transaction
+// that makes them necessary. The outbox relay enqueues them once that transaction has committed, so a
+// task is never enqueued for a change that was rolled back, and the change does not depend on redis.
+type OutboxTaskDistributor struct {
+	q db.Querier
+}
+
+func NewOutboxTaskDistributor(q db.Querier) TaskDistributor {
+	return &OutboxTaskDistributor{
+		q: q,
+	}
+}
+
+// DistributeTask writes the task to the outbox. Only the queue and max retry options can be kept there.
+func (distributor *OutboxTaskDistributor) DistributeTask(ctx context.Context, task *asynq.Task, opts ...asynq.Option) error {
+	arg := db.CreateOutboxMessageParams{
+		TaskType: task.Type(),
+		Payload:  task.Payload(),
+		Queue:    QueueDefault,
+		MaxRetry: outboxDefaultMaxRetry,
+	}
+	for _, opt := range opts {
+		switch opt.Type() {
+		case asynq.QueueOpt:
+			arg.Queue = opt.Value().(string)
+		case asynq.MaxRetryOpt:
+			arg.MaxRetry = int32(opt.Value().(int))
+		default:
+			return fmt.Errorf("unsupported outbox task option: %s", opt)
+		}
+	}
+
+	message, err := distributor.q.CreateOutboxMessage(ctx, arg)
+	if err != nil {
+		return fmt.Errorf("failed to add task to outbox: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Int64("outbox_id", message.ID).Msg("added task to outbox")
+	return nil
+}
+
+func (distributor *OutboxTaskDistributor) DistributeTaskSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opts ...asynq.Option) error {
+	return distributeTask(ctx, distributor, TaskSendVerifyEmail, payload, opts...)
+}
+
+func (distributor *OutboxTaskDistributor) DistributeTaskSendPasswordReset(ctx context.Context, payload *PayloadSendPasswordReset, opts ...asynq.Option) error {
+	return distributeTask(ctx, distributor, TaskSendPasswordReset, payload, opts...)
+}
+
+func (distributor *OutboxTaskDistributor) DistributeTaskSendAccountLocked(ctx context.Context, payload *PayloadSendAccountLocked, opts ...asynq.Option) error {
+	return distributeTask(ctx, distributor, TaskSendAccountLocked, payload, opts...)
+}
+
+func (distributor *OutboxTaskDistributor) DistributeTaskSendEmailChanged(ctx context.Context, payload *PayloadSendEmailChanged, opts ...asynq.Option) error {
+	return distributeTask(ctx, distributor, TaskSendEmailChanged, payload, opts...)
+}
+
+func (distributor *OutboxTaskDistributor) DistributeTaskSendEmailChangeRequested(ctx context.Context, payload *PayloadSendEmailChangeRequested, opts ...asynq.Option) error {
+	return distributeTask(ctx, distributor, TaskSendEmailChangeRequested, payload, opts...)
+}
+
+func (distributor *OutboxTaskDistributor) DistributeTaskSendTransferNotification(ctx context.Context, payload *PayloadSendTransferNotification, opts ...asynq.Option) error {
+	return distributeTask(ctx, distributor, TaskSendTransferNotification, payload, opts...)
+}
@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// outboxRelayInterval is how often the outbox is looked up for unsent messages
+	outboxRelayInterval = time.Second
+	// outboxRelayBatchSize is the most messages relayed in one transaction
+	outboxRelayBatchSize = 100
+	// outboxTaskRetention is how long a relayed task is kept after it is processed,
+	// so that relaying its message again is still recognized as a duplicate
+	outboxTaskRetention = 24 * time.Hour
+)
+
+// OutboxRelay hands the messages of the outbox off to the task queue through a TaskDistributor, and marks them sent.
+//
+// A message is enqueued with a task id made from its row. When it is relayed again because marking it sent
+// did not commit, the task queue rejects the duplicate id and the message is just marked sent, so every
+// message is handed off exactly once.
+type OutboxRelay struct {
+	store       db.Store
+	distributor TaskDistributor
+}
+
+func NewOutboxRelay(store db.Store, distributor TaskDistributor) *OutboxRelay {
+	return &OutboxRelay{
+		store:       store,
+		distributor: distributor,
+	}
+}
+
+// Start relays the outbox until ctx is done
+func (relay *OutboxRelay) Start(ctx context.Context) {
+	ticker := time.NewTicker(outboxRelayInterval)
+	defer ticker.Stop()
+
+	for {
+		// a full batch means more messages may be waiting
+		for relay.relay(ctx) == outboxRelayBatchSize {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay relays one batch of messages and returns how many were sent
+func (relay *OutboxRelay) relay(ctx context.Context) int {
+	result, err := relay.store.RelayOutboxTx(ctx, db.RelayOutboxTxParams{
+		Limit: outboxRelayBatchSize,
+		Publish: func(message db.OutboxMessage) error {
+			return relay.publish(ctx, message)
+		},
+	})
+	if err != nil && ctx.Err() == nil {
+		log.Error().Err(err).Int("sent", len(result.Sent)).Msg("failed to relay outbox")
+	}
+	return len(result.Sent)
+}
+
+func (relay *OutboxRelay) publish(ctx context.Context, message db.OutboxMessage) error {
+	task := asynq.NewTask(message.TaskType, message.Payload)
+	err := relay.distributor.DistributeTask(
+		ctx,
+		task,
+		asynq.Queue(message.Queue),
+		asynq.MaxRetry(int(message.MaxRetry)),
+		// the creation time keeps ids unique when the outbox ids start over, e.g. on a new database
+		asynq.TaskID(fmt.Sprintf("outbox:%d:%d", message.ID, message.CreatedAt.Time.UnixMicro())),
+		asynq.Retention(outboxTaskRetention),
+	)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		log.Info().Str("type", task.Type()).Int64("outbox_id", message.ID).Msg("outbox message was already enqueued")
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog"
+)
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+// recordingDistributor records the tasks the relay enqueues, and fails the next ones with errs
+type recordingDistributor struct {
+	TaskDistributor
+	tasks []*asynq.Task
+	opts  [][]asynq.Option
+	errs  []error
+}
+
+func (distributor *recordingDistributor) DistributeTask(ctx context.Context, task *asynq.Task, opts ...asynq.Option) error {
+	if len(distributor.errs) > 0 {
+		err := distributor.errs[0]
+		distributor.errs = distributor.errs[1:]
+		return err
+	}
+	distributor.tasks = append(distributor.tasks, task)
+	distributor.opts = append(distributor.opts, opts)
+	return nil
+}
+
+func listUnsentOutboxMessages(t *testing.T, store db.Store) []db.OutboxMessage {
+	messages, err := store.ListUnsentOutboxMessages(context.Background(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return messages
+}
+
+func TestOutboxRelay(t *testing.T) {
+	ctx := context.Background()
+	store := db.NewMemoryStore()
+	outbox := NewOutboxTaskDistributor(store)
+
+	err := outbox.DistributeTaskSendVerifyEmail(ctx, &PayloadSendVerifyEmail{Username: "alice"}, asynq.Queue(QueueCrirical), asynq.MaxRetry(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = outbox.DistributeTaskSendPasswordReset(ctx, &PayloadSendPasswordReset{Username: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	distributor := &recordingDistributor{}
+	relay := NewOutboxRelay(store, distributor)
+	if sent := relay.relay(ctx); sent != 2 {
+		t.Fatalf("relayed %d messages, want 2", sent)
+	}
+	if messages := listUnsentOutboxMessages(t, store); len(messages) != 0 {
+		t.Fatalf("%d messages left unsent", len(messages))
+	}
+	if distributor.tasks[0].Type() != TaskSendVerifyEmail || distributor.tasks[1].Type() != TaskSendPasswordReset {
+		t.Fatalf("relayed %s and %s", distributor.tasks[0].Type(), distributor.tasks[1].Type())
+	}
+
+	// the task keeps the options it was distributed with, and gets an id made from its message
+	want := map[asynq.OptionType]any{
+		asynq.QueueOpt:    QueueCrirical,
+		asynq.MaxRetryOpt: 10,
+	}
+	for _, opt := range distributor.opts[0] {
+		if value, ok := want[opt.Type()]; ok && opt.Value() != value {
+			t.Fatalf("relayed with %s, want %v", opt, value)
+		}
+		if opt.Type() == asynq.TaskIDOpt && opt.Value() == "" {
+			t.Fatal("relayed without a task id")
+		}
+	}
+
+	// relaying again does not enqueue anything
+	if sent := relay.relay(ctx); sent != 0 || len(distributor.tasks) != 2 {
+		t.Fatalf("relayed %d messages again", sent)
+	}
+}
+
+func TestOutboxRelayFailure(t *testing.T) {
+	ctx := context.Background()
+	store := db.NewMemoryStore()
+	outbox := NewOutboxTaskDistributor(store)
+	for _, username := range []string{"alice", "bob"} {
+		if err := outbox.DistributeTaskSendPasswordReset(ctx, &PayloadSendPasswordReset{Username: username}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a message that can't be enqueued stays in the outbox, along with the ones after it
+	distributor := &recordingDistributor{errs: []error{errors.New("redis is down")}}
+	relay := NewOutboxRelay(store, distributor)
+	if sent := relay.relay(ctx); sent != 0 {
+		t.Fatalf("relayed %d messages, want 0", sent)
+	}
+	if messages := listUnsentOutboxMessages(t, store); len(messages) != 2 {
+		t.Fatalf("%d messages left unsent, want 2", len(messages))
+	}
+
+	// a task that was already enqueued, before marking its message sent failed, is not enqueued twice
+	distributor.errs = []error{asynq.ErrTaskIDConflict}
+	if sent := relay.relay(ctx); sent != 2 {
+		t.Fatalf("relayed %d messages, want 2", sent)
+	}
+	if len(distributor.tasks) != 1 {
+		t.Fatalf("enqueued %d tasks, want 1", len(distributor.tasks))
+	}
+	if messages := listUnsentOutboxMessages(t, store); len(messages) != 0 {
+		t.Fatalf("%d messages left unsent", len(messages))
+	}
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendPasswordReset = "task:send_password_reset"
+
+// passwordResetExpiresIn matches the expiry the password_resets table gives a new link
+const passwordResetExpiresIn = 15 * time.Minute
+
+type PayloadSendPasswordReset struct {
+	Username string `json:"username"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendPasswordReset(ctx context.Context, payload *PayloadSendPasswordReset, opts ...asynq.Option) error {
+	return distributeTask(ctx, distributor, TaskSendPasswordReset, payload, opts...)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendPasswordReset(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendPasswordReset
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	passwordReset, err := processor.store.CreatePasswordReset(ctx, db.CreatePasswordResetParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: uuid.New().String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+
+	// send email to user
+	err = processor.sendEmail("password_reset", user, user.Email, struct {
+		FullName         string
+		URL              string
+		ExpiresInMinutes int
+	}{
+		FullName: user.Fullname,
+		// the page collects the new password and posts it to the ResetPassword API along with the reset
+		URL: processor.templates.PageURL("/reset_password", url.Values{
+			"reset_id":    {strconv.FormatInt(passwordReset.ID, 10)},
+			"secret_code": {passwordReset.SecretCode},
+		}),
+		ExpiresInMinutes: int(passwordResetExpiresIn / time.Minute),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Str("email", user.Email).Msg("processed task")
+	return nil
+}
@@ -2,6 +2,8 @@ package worker
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	db "github.com/absk07/Go-Bank/db/sqlc"
 	"github.com/absk07/Go-Bank/utils"
@@ -18,15 +20,26 @@ type TaskProcessor interface {
 	Start() error
 	Shutdown()
 	ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendPasswordReset(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendAccountLocked(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendEmailChanged(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendEmailChangeRequested(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendTransferNotification(ctx context.Context, task *asynq.Task) error
+	ProcessTaskDeliverWebhook(ctx context.Context, task *asynq.Task) error
+	ProcessTaskScheduledTransfers(ctx context.Context, task *asynq.Task) error
+	ProcessTaskPruneFailedLogins(ctx context.Context, task *asynq.Task) error
+	ProcessTaskPrunePasswordResetRequests(ctx context.Context, task *asynq.Task) error
 }
 
 type RedisTaskProcessor struct {
 	server *asynq.Server
-	store *db.Store
+	store db.Store
 	mailer utils.EmailSender
+	templates *utils.EmailTemplates
+	webhookClient *http.Client
 }
 
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store *db.Store, mailer utils.EmailSender) TaskProcessor {
+func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer utils.EmailSender, templates *utils.EmailTemplates) TaskProcessor {
 	logger := NewLogger()
 
 	server := asynq.NewServer(
@@ -36,6 +49,12 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store *db.Store, maile
 				QueueCrirical: 10,
 				QueueDefault: 5,
 			},
+			RetryDelayFunc: func(retried int, err error, task *asynq.Task) time.Duration {
+				if task.Type() == TaskDeliverWebhook {
+					return webhookRetryDelay(retried)
+				}
+				return asynq.DefaultRetryDelayFunc(retried, err, task)
+			},
 			ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
 				log.Error().Err(err).Str("type", task.Type()).Bytes("payload", task.Payload()).Msg("process task failed")
 			}),
@@ -47,6 +66,8 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store *db.Store, maile
 		server: server,
 		store: store,
 		mailer: mailer,
+		templates: templates,
+		webhookClient: newWebhookClient(),
 	}
 }
 
@@ -54,10 +75,28 @@ func (processor *RedisTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
 
 	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessTaskSendVerifyEmail)
+	mux.HandleFunc(TaskSendPasswordReset, processor.ProcessTaskSendPasswordReset)
+	mux.HandleFunc(TaskSendAccountLocked, processor.ProcessTaskSendAccountLocked)
+	mux.HandleFunc(TaskSendEmailChanged, processor.ProcessTaskSendEmailChanged)
+	mux.HandleFunc(TaskSendEmailChangeRequested, processor.ProcessTaskSendEmailChangeRequested)
+	mux.HandleFunc(TaskSendTransferNotification, processor.ProcessTaskSendTransferNotification)
+	mux.HandleFunc(TaskDeliverWebhook, processor.ProcessTaskDeliverWebhook)
+	mux.HandleFunc(TaskProcessScheduledTransfers, processor.ProcessTaskScheduledTransfers)
+	mux.HandleFunc(TaskPruneFailedLogins, processor.ProcessTaskPruneFailedLogins)
+	mux.HandleFunc(TaskPrunePasswordResetRequests, processor.ProcessTaskPrunePasswordResetRequests)
 
 	return processor.server.Start(mux)
 }
 
 func (processor *RedisTaskProcessor) Shutdown() {
 	processor.server.Shutdown()
-}
\ No newline at end of file
+}
+
+// sendEmail renders the email template name in the locale of the user and sends it to the given address
+func (processor *RedisTaskProcessor) sendEmail(name string, user db.User, to string, data any) error {
+	content, err := processor.templates.Render(name, user.Locale, data)
+	if err != nil {
+		return err
+	}
+	return processor.mailer.SendEmail(content, []string{to}, nil, nil, nil)
+}
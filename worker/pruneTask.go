@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TaskPruneFailedLogins          = "task:prune_failed_logins"
+	TaskPrunePasswordResetRequests = "task:prune_password_reset_requests"
+
+	// pruneInterval is how often the rows only kept to throttle requests are pruned
+	pruneInterval = "@every 1h"
+	// failedLoginRetention and passwordResetRequestRetention are how long failed logins and
+	// password reset requests are kept, longer than the api counts them for
+	failedLoginRetention          = 24 * time.Hour
+	passwordResetRequestRetention = 24 * time.Hour
+)
+
+// ProcessTaskPruneFailedLogins deletes the failed logins too old to be counted anymore. The lockout of a user
+// is counted on the user, so it is kept. It is enqueued periodically by the TaskScheduler.
+func (processor *RedisTaskProcessor) ProcessTaskPruneFailedLogins(ctx context.Context, task *asynq.Task) error {
+	deleted, err := processor.store.DeleteFailedLoginsBefore(ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(-failedLoginRetention),
+		Valid: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete failed logins: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("deleted", deleted).Msg("processed task")
+	return nil
+}
+
+// ProcessTaskPrunePasswordResetRequests deletes the password reset requests too old to be counted anymore.
+// It is enqueued periodically by the TaskScheduler.
+func (processor *RedisTaskProcessor) ProcessTaskPrunePasswordResetRequests(ctx context.Context, task *asynq.Task) error {
+	deleted, err := processor.store.DeletePasswordResetRequestsBefore(ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(-passwordResetRequestRetention),
+		Valid: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete password reset requests: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("deleted", deleted).Msg("processed task")
+	return nil
+}
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TaskProcessScheduledTransfers = "task:process_scheduled_transfers"
+
+	// scheduledTransfersBatchSize is the maximum number of due schedules run by one task
+	scheduledTransfersBatchSize = 100
+)
+
+// ProcessTaskScheduledTransfers runs the scheduled transfers that are due. It is enqueued
+// periodically by the TaskScheduler, so a schedule that can't be run now is picked up again
+// by one of the next tasks.
+func (processor *RedisTaskProcessor) ProcessTaskScheduledTransfers(ctx context.Context, task *asynq.Task) error {
+	now := time.Now()
+
+	scheduledTransfers, err := processor.store.ListDueScheduledTransfers(ctx, db.ListDueScheduledTransfersParams{
+		Now:        pgtype.Timestamptz{Time: now, Valid: true},
+		LimitCount: scheduledTransfersBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list due scheduled transfers: %w", err)
+	}
+
+	for _, scheduledTransfer := range scheduledTransfers {
+		result, err := processor.store.RunScheduledTransferTx(ctx, db.RunScheduledTransferTxParams{
+			ID:            scheduledTransfer.ID,
+			Now:           now,
+			AfterTransfer: AfterTransfer(ctx),
+		})
+		if err != nil {
+			if errors.Is(err, db.ErrScheduleNotDue) {
+				continue
+			}
+			log.Error().Err(err).Int64("scheduled_transfer_id", scheduledTransfer.ID).Msg("failed to run scheduled transfer")
+			continue
+		}
+
+		log.Info().
+			Str("type", task.Type()).
+			Int64("scheduled_transfer_id", scheduledTransfer.ID).
+			Str("run_status", result.Run.Status).
+			Str("run_error", result.Run.Error).
+			Str("schedule_status", result.ScheduledTransfer.Status).
+			Msg("processed scheduled transfer")
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+// scheduledTransfersInterval is how often the due scheduled transfers are looked up
+const scheduledTransfersInterval = "@every 1m"
+
+type TaskScheduler interface {
+	Start() error
+	Shutdown()
+}
+
+// RedisTaskScheduler enqueues the periodic tasks of the worker
+type RedisTaskScheduler struct {
+	scheduler *asynq.Scheduler
+}
+
+func NewRedisTaskScheduler(redisOpt asynq.RedisClientOpt) TaskScheduler {
+	logger := NewLogger()
+
+	scheduler := asynq.NewScheduler(
+		redisOpt,
+		&asynq.SchedulerOpts{
+			Logger: logger,
+			EnqueueErrorHandler: func(task *asynq.Task, opts []asynq.Option, err error) {
+				log.Error().Err(err).Str("type", task.Type()).Msg("enqueue periodic task failed")
+			},
+		},
+	)
+
+	return &RedisTaskScheduler{
+		scheduler: scheduler,
+	}
+}
+
+func (scheduler *RedisTaskScheduler) Start() error {
+	// runs are recorded on the schedules themselves, a failed task is just picked up by the next one
+	_, err := scheduler.scheduler.Register(
+		scheduledTransfersInterval,
+		asynq.NewTask(TaskProcessScheduledTransfers, nil),
+		asynq.Queue(QueueCrirical),
+		asynq.MaxRetry(0),
+	)
+	if err != nil {
+		return err
+	}
+
+	// the rows only kept to throttle requests are pruned by the next task when one fails
+	for _, taskType := range []string{TaskPruneFailedLogins, TaskPrunePasswordResetRequests} {
+		_, err = scheduler.scheduler.Register(
+			pruneInterval,
+			asynq.NewTask(taskType, nil),
+			asynq.Queue(QueueDefault),
+			asynq.MaxRetry(0),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return scheduler.scheduler.Start()
+}
+
+func (scheduler *RedisTaskScheduler) Shutdown() {
+	scheduler.scheduler.Shutdown()
+}
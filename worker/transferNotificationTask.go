@@ -0,0 +1,167 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendTransferNotification = "task:send_transfer_notification"
+
+// the sides of a transfer a notification is sent for
+const (
+	TransferDebit  = "debit"
+	TransferCredit = "credit"
+)
+
+type PayloadSendTransferNotification struct {
+	TransferID int64 `json:"transfer_id"`
+	// Direction is TransferDebit for the sender of the transfer and TransferCredit for its recipient
+	Direction             string `json:"direction"`
+	AccountID             int64  `json:"account_id"`
+	CounterpartyAccountID int64  `json:"counterparty_account_id"`
+	// Amount is in the currency of the account, and Balance is what the transfer left the account with
+	Amount  int64 `json:"amount"`
+	Balance int64 `json:"balance"`
+}
+
+// AfterTransfer is the callback of the transactions that move money. Through the outbox of the transaction, it
+// notifies the sender and the recipient, and distributes the transfer.created webhook event to both of them.
+func AfterTransfer(ctx context.Context) func(q db.Querier, result db.TransferTxResult) error {
+	return func(q db.Querier, result db.TransferTxResult) error {
+		distributor := NewOutboxTaskDistributor(q)
+
+		opts := []asynq.Option{
+			asynq.MaxRetry(10),
+			asynq.Queue(QueueDefault),
+		}
+		if err := DistributeTransferNotifications(ctx, distributor, result, opts...); err != nil {
+			return err
+		}
+
+		data := TransferEventData{
+			Transfer:           result.Transfer,
+			SettlementTransfer: result.SettlementTransfer,
+		}
+		return DistributeWebhookEvent(ctx, q, distributor, utils.WebhookEventTransferCreated, data, result.FromAccount.Owner, result.ToAccount.Owner)
+	}
+}
+
+// DistributeTransferNotifications distributes the debit notification of the sender and the credit
+// notification of the recipient of a transfer. System accounts are not notified.
+func DistributeTransferNotifications(ctx context.Context, distributor TaskDistributor, result db.TransferTxResult, opts ...asynq.Option) error {
+	payloads := []*PayloadSendTransferNotification{
+		{
+			TransferID:            result.Transfer.ID,
+			Direction:             TransferDebit,
+			AccountID:             result.FromAccount.ID,
+			CounterpartyAccountID: result.ToAccount.ID,
+			Amount:                -result.FromEntry.Amount,
+			Balance:               result.FromAccount.Balance,
+		},
+		{
+			TransferID:            result.Transfer.ID,
+			Direction:             TransferCredit,
+			AccountID:             result.ToAccount.ID,
+			CounterpartyAccountID: result.FromAccount.ID,
+			Amount:                result.ToEntry.Amount,
+			Balance:               result.ToAccount.Balance,
+		},
+	}
+	accounts := []db.Account{result.FromAccount, result.ToAccount}
+
+	for i, payload := range payloads {
+		if accounts[i].IsSystem() {
+			continue
+		}
+		if err := distributor.DistributeTaskSendTransferNotification(ctx, payload, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendTransferNotification(ctx context.Context, payload *PayloadSendTransferNotification, opts ...asynq.Option) error {
+	return distributeTask(ctx, distributor, TaskSendTransferNotification, payload, opts...)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendTransferNotification(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendTransferNotification
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	var template string
+	switch payload.Direction {
+	case TransferDebit:
+		template = "transfer_debit"
+	case TransferCredit:
+		template = "transfer_credit"
+	default:
+		return fmt.Errorf("unknown transfer direction %q: %w", payload.Direction, asynq.SkipRetry)
+	}
+
+	account, err := processor.store.GetAccount(ctx, payload.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	user, err := processor.store.GetUser(ctx, account.Owner)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	preference, err := processor.store.GetNotificationPreference(ctx, db.GetNotificationPreferenceParams{
+		Username: user.Username,
+		Channel:  utils.EmailChannel,
+	})
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get notification preference: %w", err)
+	}
+	// without a preference, the user has not opted out
+	if err == nil && !preference.TransferNotifications {
+		log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Msg("skipped task, user opted out of transfer emails")
+		return nil
+	}
+
+	counterpartyAccount, err := processor.store.GetAccount(ctx, payload.CounterpartyAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get counterparty account: %w", err)
+	}
+	counterparty, err := processor.store.GetUser(ctx, counterpartyAccount.Owner)
+	if err != nil {
+		return fmt.Errorf("failed to get counterparty: %w", err)
+	}
+
+	// send email to user
+	err = processor.sendEmail(template, user, user.Email, struct {
+		FullName              string
+		TransferID            int64
+		AccountID             int64
+		Amount                int64
+		Currency              string
+		Balance               int64
+		CounterpartyName      string
+		CounterpartyAccountID int64
+	}{
+		FullName:              user.Fullname,
+		TransferID:            payload.TransferID,
+		AccountID:             account.ID,
+		Amount:                payload.Amount,
+		Currency:              account.Currency,
+		Balance:               payload.Balance,
+		CounterpartyName:      counterparty.Fullname,
+		CounterpartyAccountID: counterpartyAccount.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send transfer notification: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Str("email", user.Email).Msg("processed task")
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
 
 	db "github.com/absk07/Go-Bank/db/sqlc"
 	"github.com/google/uuid"
@@ -15,22 +17,12 @@ const TaskSendVerifyEmail = "task:send_verify_email"
 
 type PayloadSendVerifyEmail struct {
 	Username string `json:"username"`
+	// Email is the changed email of the user waiting for verification, empty for the email the user registered with
+	Email string `json:"email,omitempty"`
 }
 
 func (distributor *RedisTaskDistributor) DistributeTaskSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opts ...asynq.Option) error {
-	json_payload, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal task payload: %w", err)
-	}
-
-	task := asynq.NewTask(TaskSendVerifyEmail, json_payload, opts...)
-	info, err := distributor.client.EnqueueContext(ctx, task)
-	if err != nil {
-		return fmt.Errorf("failed to enqueue task: %w", err)
-	}
-
-	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
-	return nil
+	return distributeTask(ctx, distributor, TaskSendVerifyEmail, payload, opts...)
 }
 
 func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error {
@@ -44,9 +36,19 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 		return fmt.Errorf("failed to get user: %w", err)
 	}
 
+	email := user.Email
+	if payload.Email != "" {
+		// the email was changed again before this task ran
+		if !user.PendingEmail.Valid || user.PendingEmail.String != payload.Email {
+			log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Msg("skipped task, email is no longer pending")
+			return nil
+		}
+		email = payload.Email
+	}
+
 	verifyEmail, err := processor.store.CreateVerifyEmail(ctx, db.CreateVerifyEmailParams{
 		Username:   user.Username,
-		Email:      user.Email,
+		Email:      email,
 		SecretCode: uuid.New().String(),
 	})
 	if err != nil {
@@ -54,20 +56,24 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 	}
 
 	// send email to user
-	subject := "Welcome to Go-Bank"
-	verifyUrl := fmt.Sprintf("http://localhost:8080/v1/verify_email?email_id=%d&secret_code=%s", verifyEmail.ID, verifyEmail.SecretCode)
-	content := fmt.Sprintf(
-		`Hello %s,<br/>
-		Thank you for registering with us!<br/>
-		Please <a href="%s">click here</a> to verify your email address.<br/>`, user.Fullname, verifyUrl,
-	)
-	to := []string{user.Email}
-
-	err = processor.mailer.SendEmail(subject, content, to, nil, nil, nil)
+	template := "verify_email"
+	if payload.Email != "" {
+		template = "verify_new_email"
+	}
+	err = processor.sendEmail(template, user, email, struct {
+		FullName string
+		URL      string
+	}{
+		FullName: user.Fullname,
+		URL: processor.templates.URL("/v1/verify_email", url.Values{
+			"email_id":    {strconv.FormatInt(verifyEmail.ID, 10)},
+			"secret_code": {verifyEmail.SecretCode},
+		}),
+	})
 	if err != nil {
 		return fmt.Errorf("failed to send verify email: %w", err)
 	}
 
-	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Str("email", user.Email).Msg("processed task")
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Str("email", email).Msg("processed task")
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,250 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskDeliverWebhook = "task:deliver_webhook"
+
+const (
+	// webhookMaxRetry and the retry delays give an endpoint about a day and a half to come back
+	webhookMaxRetry       = 10
+	webhookRetryBaseDelay = 30 * time.Second
+	webhookMaxRetryDelay  = 6 * time.Hour
+	// webhookTimeout is how long an endpoint has to respond
+	webhookTimeout = 10 * time.Second
+	// webhookResponseBodyLimit is how much of a response is read, so that the connection can be reused.
+	// The body itself is never kept.
+	webhookResponseBodyLimit = 1024
+)
+
+// WebhookEvent is the JSON body delivered to webhook endpoints
+type WebhookEvent struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// TransferEventData is the data of transfer.created events
+type TransferEventData struct {
+	Transfer db.Transfer `json:"transfer"`
+	// SettlementTransfer is the leg that credits the recipient of a cross-currency transfer
+	SettlementTransfer *db.Transfer `json:"settlement_transfer,omitempty"`
+}
+
+// AccountEventData is the data of account.created events
+type AccountEventData struct {
+	Account db.Account `json:"account"`
+}
+
+// UserEventData is the data of user.email_verified events
+type UserEventData struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type PayloadDeliverWebhook struct {
+	EndpointID int64        `json:"endpoint_id"`
+	Event      WebhookEvent `json:"event"`
+}
+
+// DistributeWebhookEvent distributes a delivery of an event to every endpoint of owners subscribed to eventType.
+// The endpoints are looked up with q, so that the deliveries are distributed in the transaction of the event.
+func DistributeWebhookEvent(ctx context.Context, q db.Querier, distributor TaskDistributor, eventType string, data any, owners ...string) error {
+	endpoints, err := q.ListWebhookEndpointsForEvent(ctx, db.ListWebhookEndpointsForEventParams{
+		Owners:    owners,
+		EventType: eventType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	json_data, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook event data: %w", err)
+	}
+	event := WebhookEvent{
+		ID:        uuid.New(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      json_data,
+	}
+
+	opts := []asynq.Option{
+		asynq.MaxRetry(webhookMaxRetry),
+		asynq.Queue(QueueDefault),
+	}
+	for _, endpoint := range endpoints {
+		taskPayload := &PayloadDeliverWebhook{
+			EndpointID: endpoint.ID,
+			Event:      event,
+		}
+		if err := distributeTask(ctx, distributor, TaskDeliverWebhook, taskPayload, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// webhookRetryDelay doubles the delay before every retry of a delivery, up to webhookMaxRetryDelay
+func webhookRetryDelay(retried int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 0; i < retried && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxRetryDelay)
+}
+
+// ProcessTaskDeliverWebhook posts an event to an endpoint, and logs the attempt. A failed attempt fails the task,
+// so that asynq retries it after webhookRetryDelay.
+func (processor *RedisTaskProcessor) ProcessTaskDeliverWebhook(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadDeliverWebhook
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	endpoint, err := processor.store.GetWebhookEndpoint(ctx, payload.EndpointID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			log.Info().Str("type", task.Type()).Int64("endpoint_id", payload.EndpointID).Msg("skipped task, webhook endpoint was deleted")
+			return nil
+		}
+		return fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+
+	body, err := json.Marshal(payload.Event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	delivery := processor.postWebhook(ctx, endpoint, payload.Event, body)
+	retried, _ := asynq.GetRetryCount(ctx)
+	delivery.Attempt = int32(retried + 1)
+
+	_, err = processor.store.CreateWebhookDelivery(ctx, delivery)
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			log.Info().Str("type", task.Type()).Int64("endpoint_id", payload.EndpointID).Msg("skipped task, webhook endpoint was deleted")
+			return nil
+		}
+		return fmt.Errorf("failed to log webhook delivery: %w", err)
+	}
+	if !delivery.Succeeded {
+		return fmt.Errorf("failed to deliver webhook: %s", delivery.Error)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("endpoint_id", endpoint.ID).Str("event_id", payload.Event.ID.String()).Msg("processed task")
+	return nil
+}
+
+// postWebhook posts a signed event to an endpoint, and returns the log of the attempt
+func (processor *RedisTaskProcessor) postWebhook(ctx context.Context, endpoint db.WebhookEndpoint, event WebhookEvent, body []byte) db.CreateWebhookDeliveryParams {
+	delivery := db.CreateWebhookDeliveryParams{
+		EndpointID: endpoint.ID,
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Payload:    body,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Go-Bank-Webhooks")
+	req.Header.Set(utils.WebhookEventHeader, event.Type)
+	req.Header.Set(utils.WebhookDeliveryHeader, event.ID.String())
+	req.Header.Set(utils.WebhookSignatureHeader, utils.SignWebhookPayload(endpoint.Secret, time.Now(), body))
+
+	start := time.Now()
+	rsp, err := processor.webhookClient.Do(req)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Error = webhookErrorSummary(err)
+		return delivery
+	}
+	defer rsp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(rsp.Body, webhookResponseBodyLimit))
+	delivery.ResponseStatus = pgtype.Int4{Int32: int32(rsp.StatusCode), Valid: true}
+	delivery.Succeeded = rsp.StatusCode >= 200 && rsp.StatusCode < 300
+	if !delivery.Succeeded {
+		delivery.Error = fmt.Sprintf("endpoint responded with status %d", rsp.StatusCode)
+	}
+	return delivery
+}
+
+// newWebhookClient creates the client webhooks are delivered with. It only connects to public addresses,
+// which are checked once a name is resolved, so that a name resolving to an internal address is turned away too.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: webhookDialControl,
+	}
+	return &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			// a proxy would connect to the endpoint on the worker's behalf, past the address check
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: webhookTimeout,
+		},
+		// a redirect is a failed delivery, the signature is only meant for the registered url
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// webhookDialControl rejects connections to addresses that are not public, right before they are made
+func webhookDialControl(network string, address string, conn syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil || !utils.IsPublicWebhookAddress(addrPort.Addr()) {
+		return utils.ErrWebhookAddressNotAllowed
+	}
+	return nil
+}
+
+// webhookErrorSummary describes why a delivery failed without the details of the error,
+// which could tell the owner of the endpoint about the network of the bank
+func webhookErrorSummary(err error) string {
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	switch {
+	case errors.Is(err, utils.ErrWebhookAddressNotAllowed):
+		return "endpoint address is not allowed"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "endpoint did not respond in time"
+	case errors.As(err, &dnsErr):
+		return "endpoint host could not be resolved"
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return "could not connect to endpoint"
+	}
+	return "request to endpoint failed"
+}
@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/absk07/Go-Bank/db/sqlc"
+	"github.com/absk07/Go-Bank/utils"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+func newWebhookTask(t *testing.T, endpointID int64) *asynq.Task {
+	payload, err := json.Marshal(&PayloadDeliverWebhook{
+		EndpointID: endpointID,
+		Event: WebhookEvent{
+			ID:        uuid.New(),
+			Type:      utils.WebhookEventTransferCreated,
+			CreatedAt: time.Now(),
+			Data:      json.RawMessage(`{"transfer_id":1}`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return asynq.NewTask(TaskDeliverWebhook, payload)
+}
+
+func createTestWebhookEndpoint(t *testing.T, store db.Store, url string) db.WebhookEndpoint {
+	ctx := context.Background()
+	_, err := store.CreateUser(ctx, db.CreateUserParams{Username: "alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint, err := store.CreateWebhookEndpoint(ctx, db.CreateWebhookEndpointParams{
+		Owner:      "alice",
+		Url:        url,
+		Secret:     "whsec_test",
+		EventTypes: []string{utils.WebhookEventTransferCreated},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return endpoint
+}
+
+func listWebhookDeliveries(t *testing.T, store db.Store, endpointID int64) []db.WebhookDelivery {
+	deliveries, err := store.ListWebhookDeliveries(context.Background(), db.ListWebhookDeliveriesParams{EndpointID: endpointID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deliveries
+}
+
+func TestProcessTaskDeliverWebhook(t *testing.T) {
+	ctx := context.Background()
+	store := db.NewMemoryStore()
+
+	var signature string
+	var body []byte
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(utils.WebhookSignatureHeader)
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		w.Write([]byte("internal details"))
+	}))
+	defer server.Close()
+
+	endpoint := createTestWebhookEndpoint(t, store, server.URL)
+	// the test server listens on loopback, which the webhook client refuses to connect to
+	processor := &RedisTaskProcessor{store: store, webhookClient: server.Client()}
+
+	if err := processor.ProcessTaskDeliverWebhook(ctx, newWebhookTask(t, endpoint.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.VerifyWebhookSignature(endpoint.Secret, signature, body, time.Now(), time.Minute); err != nil {
+		t.Fatalf("delivery is not signed: %s", err)
+	}
+
+	status = http.StatusInternalServerError
+	if err := processor.ProcessTaskDeliverWebhook(ctx, newWebhookTask(t, endpoint.ID)); err == nil {
+		t.Fatal("failed delivery was not retried")
+	}
+
+	deliveries := listWebhookDeliveries(t, store, endpoint.ID)
+	if len(deliveries) != 2 {
+		t.Fatalf("logged %d deliveries, want 2", len(deliveries))
+	}
+	failed := deliveries[0]
+	if failed.Succeeded || failed.ResponseStatus.Int32 != http.StatusInternalServerError || failed.Error != "endpoint responded with status 500" {
+		t.Fatalf("failed delivery is logged as %+v", failed)
+	}
+	if !deliveries[1].Succeeded {
+		t.Fatalf("delivery is logged as %+v", deliveries[1])
+	}
+}
+
+func TestProcessTaskDeliverWebhookToInternalAddress(t *testing.T) {
+	ctx := context.Background()
+	store := db.NewMemoryStore()
+
+	delivered := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered = true
+	}))
+	defer server.Close()
+
+	// the url passed validation with a name that resolves to loopback by the time it is delivered to
+	endpoint := createTestWebhookEndpoint(t, store, server.URL)
+	processor := &RedisTaskProcessor{store: store, webhookClient: newWebhookClient()}
+
+	if err := processor.ProcessTaskDeliverWebhook(ctx, newWebhookTask(t, endpoint.ID)); err == nil {
+		t.Fatal("delivery to an internal address succeeded")
+	}
+	if delivered {
+		t.Fatal("webhook client connected to an internal address")
+	}
+	deliveries := listWebhookDeliveries(t, store, endpoint.ID)
+	if len(deliveries) != 1 || deliveries[0].Error != "endpoint address is not allowed" {
+		t.Fatalf("deliveries are logged as %+v", deliveries)
+	}
+}